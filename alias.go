@@ -0,0 +1,153 @@
+package e
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	codeAliasesMu sync.Mutex
+	codeAliases   atomic.Pointer[map[string]string]
+)
+
+// AliasCode deprecates old in favor of code, so that ErrorCode, Codes and the
+// functions built on them report code for errors which still carry old, and
+// IsCode matches either. This allows the vocabulary of error codes to evolve
+// without breaking consumers such as clients and dashboards overnight. The
+// code in Error() is not changed so that producers of old codes stay visible;
+// use a DeprecationReport to find them. An empty code removes the alias.
+//
+// Usage:
+//
+//	func init() {
+//		e.AliasCode("db_error", CodeDatabase)
+//	}
+func AliasCode(old, code string) {
+	codeAliasesMu.Lock()
+	defer codeAliasesMu.Unlock()
+
+	updated := make(map[string]string)
+	if m := codeAliases.Load(); m != nil {
+		for k, v := range *m {
+			updated[k] = v
+		}
+	}
+	if code != "" && code != old {
+		updated[old] = code
+	} else {
+		delete(updated, old)
+	}
+	codeAliases.Store(&updated)
+}
+
+// canonicalCode returns the code which code is an alias of, following chains
+// of aliases. code is returned unchanged if it is not an alias.
+func canonicalCode(code string) string {
+	m := codeAliases.Load()
+	if m == nil {
+		return code
+	}
+	// bounded so that cyclic aliases terminate
+	for range len(*m) {
+		alias, ok := (*m)[code]
+		if !ok {
+			break
+		}
+		code = alias
+	}
+	return code
+}
+
+// IsCode reports whether ErrorCode of err is code, treating codes registered
+// with AliasCode as equal to the codes they are aliases of.
+func IsCode(err error, code string) bool {
+	errCode := ErrorCode(err)
+	return errCode != "" && errCode == canonicalCode(code)
+}
+
+// DeprecatedUse is a site which produced a code deprecated with AliasCode.
+type DeprecatedUse struct {
+	Code string
+	// Replacement is the code which Code is an alias of.
+	Replacement string
+	// Op is the function which created the error or set its code.
+	Op string
+	// Caller is the file and line of the call to NewError or Wrap, if recorded
+	// with SetCallers.
+	Caller string
+}
+
+// DeprecationReport lists the sites which still produce codes deprecated with
+// AliasCode, e.g. to track a migration to new codes. Collecting is opt-in by
+// registering Observe with AddHook. The zero value is ready to use and is safe
+// for concurrent use.
+//
+// Usage:
+//
+//	var deprecated e.DeprecationReport
+//	e.AddHook(deprecated.Observe)
+//	...
+//	for _, use := range deprecated.Uses() {
+//		log.Printf("%s still returns %s instead of %s", use.Op, use.Code, use.Replacement)
+//	}
+type DeprecationReport struct {
+	mu   sync.Mutex
+	uses map[DeprecatedUse]struct{}
+}
+
+// Observe records err if it carries a deprecated code. Hooks run before
+// setters can be chained onto a new Error, so the Error wrapped by err is
+// checked as well to find codes set with SetCode. err is returned unchanged
+// so that Observe can be registered with AddHook.
+func (r *DeprecationReport) Observe(err Error) Error {
+	checked := 0
+	for err := range chain(err) {
+		impl, ok := asImpl(err)
+		if !ok {
+			continue
+		}
+		if replacement := canonicalCode(impl.code); replacement != impl.code {
+			r.add(DeprecatedUse{
+				Code:        impl.code,
+				Replacement: replacement,
+				Op:          impl.op,
+				Caller:      impl.caller.String(),
+			})
+		}
+		if checked++; checked == 2 {
+			break
+		}
+	}
+	return err
+}
+
+func (r *DeprecationReport) add(use DeprecatedUse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.uses == nil {
+		r.uses = make(map[DeprecatedUse]struct{})
+	}
+	r.uses[use] = struct{}{}
+}
+
+// Uses returns the recorded sites ordered by code, op and caller.
+func (r *DeprecationReport) Uses() []DeprecatedUse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uses := make([]DeprecatedUse, 0, len(r.uses))
+	for use := range r.uses {
+		uses = append(uses, use)
+	}
+	sort.Slice(uses, func(i, j int) bool {
+		if uses[i].Code != uses[j].Code {
+			return uses[i].Code < uses[j].Code
+		}
+		if uses[i].Op != uses[j].Op {
+			return uses[i].Op < uses[j].Op
+		}
+		return uses[i].Caller < uses[j].Caller
+	})
+	return uses
+}
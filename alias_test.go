@@ -0,0 +1,64 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAliasCode(t *testing.T) {
+	AliasCode("db_error", CodeDatabase)
+	t.Cleanup(func() { AliasCode("db_error", "") })
+
+	err := Wrap(NewError("db_error", "cannot foo"))
+	if got := ErrorCode(err); got != CodeDatabase {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeDatabase)
+	}
+	if got, want := Codes(err), []string{CodeDatabase}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	for _, code := range []string{"db_error", CodeDatabase} {
+		if !IsCode(err, code) {
+			t.Errorf("expected IsCode to match %q", code)
+		}
+		if !IsCode(Foo(), code) {
+			t.Errorf("expected IsCode of new code to match %q", code)
+		}
+	}
+	if IsCode(err, CodeNotFound) {
+		t.Errorf("expected IsCode not to match %q", CodeNotFound)
+	}
+	if got, want := WrapUnlessCode(Foo(), "db_error").Error(), "Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("expected WrapUnlessCode to match the alias but got %q", got)
+	}
+	if got, want := err.Error(), "TestAliasCode: TestAliasCode: [db_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	AliasCode("db_error", "")
+	if got := ErrorCode(err); got != "db_error" {
+		t.Errorf("expected alias to be removed but got %q", got)
+	}
+}
+
+func TestDeprecationReport(t *testing.T) {
+	AliasCode("db_error", CodeDatabase)
+	t.Cleanup(func() { AliasCode("db_error", "") })
+
+	var r DeprecationReport
+	AddHook(r.Observe)
+	t.Cleanup(hooks.reset)
+
+	newOld := func() error { return NewError("db_error", "cannot foo") }
+	setOld := func() error { return Wrap(Foo()).SetCode("db_error") }
+	_ = Wrap(newOld())
+	_ = Wrap(setOld())
+	_ = Wrap(Foo())
+
+	want := []DeprecatedUse{
+		{Code: "db_error", Replacement: CodeDatabase, Op: "TestDeprecationReport.func2"},
+		{Code: "db_error", Replacement: CodeDatabase, Op: "TestDeprecationReport.func3"},
+	}
+	if got := r.Uses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,414 @@
+// Package analyzer provides a go/analysis checker for common mistakes when
+// using package e. It reports:
+//
+//   - errors returned by functions from other packages without being wrapped
+//     with e.Wrap, which loses the op of the returning function;
+//   - setters chained onto e.Wrap, e.Wrapf or e.WrapCtx when the wrapped error
+//     may be nil. Wrap returns nil for a nil error, so the setter panics.
+//
+// Ops are recorded by NewError and Wrap automatically and therefore cannot
+// mismatch the enclosing function.
+//
+// The analyzer can be run standalone with cmd/errsvet or with go vet:
+//
+//	go vet -vettool=$(which errsvet) ./...
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const pkgPath = "github.com/kisunji/e"
+
+// Analyzer reports unwrapped errors and setters chained onto possibly-nil
+// wrapped errors.
+var Analyzer = &analysis.Analyzer{
+	Name:      "errs",
+	Doc:       "report unwrapped errors and unchecked e.Wrap chains",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(nonNilVar)},
+}
+
+// nonNilVar marks package-level variables which are initialized with a
+// non-nil error, such as sentinels created with errors.New.
+type nonNilVar struct{}
+
+func (*nonNilVar) AFact() {}
+
+func (*nonNilVar) String() string { return "nonNilVar" }
+
+// wrapErrArg maps the wrapping functions of package e to the index of their
+// error argument.
+var wrapErrArg = map[string]int{
+	"Wrap":          0,
+	"Wrapf":         0,
+	"Wrapw":         0,
+	"WrapCtx":       1,
+	"WrapLazy":      0,
+	"ClearMessages": 0,
+	"FromPkgErrors": 0,
+	"Normalize":     0,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	exportNonNilVars(pass)
+
+	nodeFilter := []ast.Node{(*ast.CallExpr)(nil), (*ast.ReturnStmt)(nil)}
+	insp.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			checkWrapChain(pass, n, stack)
+		case *ast.ReturnStmt:
+			checkUnwrapped(pass, n, stack)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// checkWrapChain reports calls like e.Wrap(err).SetCode(code) where err is
+// not known to be non-nil.
+func checkWrapChain(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	wrap, ok := ast.Unparen(sel.X).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	name, ok := wrapFunc(pass.TypesInfo, wrap)
+	if !ok || len(wrap.Args) <= wrapErrArg[name] {
+		return
+	}
+
+	arg := ast.Unparen(wrap.Args[wrapErrArg[name]])
+	if nonNil(pass, arg, stack) {
+		return
+	}
+	if ident, ok := arg.(*ast.Ident); ok {
+		if v := pass.TypesInfo.Uses[ident]; v != nil && guarded(pass.TypesInfo, v, stack) {
+			return
+		}
+	}
+	pass.Reportf(sel.Sel.Pos(), "e.%s returns nil for a nil error; check %s for nil before calling %s",
+		name, types.ExprString(arg), sel.Sel.Name)
+}
+
+// wrapFunc returns the name of the wrapping function of package e called by call.
+func wrapFunc(info *types.Info, call *ast.CallExpr) (string, bool) {
+	fn := calledFunc(info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return "", false
+	}
+	if _, ok := wrapErrArg[fn.Name()]; !ok {
+		return "", false
+	}
+	return fn.Name(), true
+}
+
+// constructors maps the full names of functions which never return a nil error.
+var constructors = map[string]bool{
+	"errors.New":               true,
+	"fmt.Errorf":               true,
+	pkgPath + ".NewError":      true,
+	pkgPath + ".NewErrorf":     true,
+	pkgPath + ".NewCtx":        true,
+	pkgPath + ".NewValidation": true,
+}
+
+// exportNonNilVars exports a nonNilVar fact for every package-level variable
+// of pass which is initialized with a non-nil error.
+func exportNonNilVars(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if len(vs.Values) != len(vs.Names) {
+					continue
+				}
+				for i, name := range vs.Names {
+					v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+					if ok && nonNil(pass, vs.Values[i], nil) {
+						pass.ExportObjectFact(v, new(nonNilVar))
+					}
+				}
+			}
+		}
+	}
+}
+
+// nonNil reports whether expr is known to be a non-nil error: a call to an
+// error constructor, a composite literal, a package-level variable initialized
+// with one of these or a local variable which is only ever assigned one of
+// these.
+func nonNil(pass *analysis.Pass, expr ast.Expr, stack []ast.Node) bool {
+	info := pass.TypesInfo
+	switch x := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		fn := calledFunc(info, x)
+		return fn != nil && constructors[fn.FullName()]
+	case *ast.CompositeLit:
+		return true
+	case *ast.UnaryExpr:
+		_, ok := x.X.(*ast.CompositeLit)
+		return x.Op == token.AND && ok
+	case *ast.Ident:
+		v, ok := info.Uses[x].(*types.Var)
+		if !ok || v.Pkg() == nil {
+			return false
+		}
+		if v.Parent() == v.Pkg().Scope() {
+			return pass.ImportObjectFact(v, new(nonNilVar))
+		}
+		rhs := soleAssignment(info, v, enclosingFunc(stack))
+		return rhs != nil && nonNil(pass, rhs, stack)
+	case *ast.SelectorExpr:
+		v, ok := info.Uses[x.Sel].(*types.Var)
+		return ok && !v.IsField() && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() &&
+			pass.ImportObjectFact(v, new(nonNilVar))
+	}
+	return false
+}
+
+// enclosingFunc returns the body of the innermost function in stack.
+func enclosingFunc(stack []ast.Node) *ast.BlockStmt {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			return fn.Body
+		case *ast.FuncLit:
+			return fn.Body
+		}
+	}
+	return nil
+}
+
+// soleAssignment returns the value assigned to v if body contains exactly one
+// assignment to v.
+func soleAssignment(info *types.Info, v types.Object, body *ast.BlockStmt) ast.Expr {
+	if body == nil {
+		return nil
+	}
+	var (
+		rhs   ast.Expr
+		count int
+	)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || (info.Defs[ident] != v && info.Uses[ident] != v) {
+					continue
+				}
+				count++
+				if len(n.Lhs) == len(n.Rhs) {
+					rhs = n.Rhs[i]
+				}
+			}
+		case *ast.UnaryExpr:
+			// taking the address allows v to be reassigned
+			if ident, ok := n.X.(*ast.Ident); ok && n.Op == token.AND && info.Uses[ident] == v {
+				count++
+			}
+		}
+		return true
+	})
+	if count != 1 {
+		return nil
+	}
+	return rhs
+}
+
+// guarded reports whether the innermost node of stack only executes when v is
+// non-nil, i.e. it is inside the body of `if v != nil` or follows
+// `if v == nil { return }` in an enclosing block.
+func guarded(info *types.Info, v types.Object, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		child := stack[i+1]
+		switch parent := stack[i].(type) {
+		case *ast.IfStmt:
+			if child == parent.Body && checksNil(info, parent.Cond, v, token.NEQ) {
+				return true
+			}
+			if child == parent.Else && checksNil(info, parent.Cond, v, token.EQL) {
+				return true
+			}
+		case *ast.BlockStmt:
+			for _, stmt := range parent.List {
+				if stmt == child {
+					break
+				}
+				ifStmt, ok := stmt.(*ast.IfStmt)
+				if ok && checksNil(info, ifStmt.Cond, v, token.EQL) && terminates(ifStmt.Body) {
+					return true
+				}
+			}
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		}
+	}
+	return false
+}
+
+// checksNil reports whether cond compares v to nil with op. Conditions joined
+// with && are searched for a comparison with token.NEQ.
+func checksNil(info *types.Info, cond ast.Expr, v types.Object, op token.Token) bool {
+	bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+	if bin.Op == token.LAND && op == token.NEQ {
+		return checksNil(info, bin.X, v, op) || checksNil(info, bin.Y, v, op)
+	}
+	if bin.Op == token.LOR && op == token.EQL {
+		return checksNil(info, bin.X, v, op) || checksNil(info, bin.Y, v, op)
+	}
+	if bin.Op != op {
+		return false
+	}
+	return isVarAndNil(info, bin.X, bin.Y, v) || isVarAndNil(info, bin.Y, bin.X, v)
+}
+
+func isVarAndNil(info *types.Info, x, y ast.Expr, v types.Object) bool {
+	ident, ok := ast.Unparen(x).(*ast.Ident)
+	if !ok || info.Uses[ident] != v {
+		return false
+	}
+	return info.Types[y].IsNil()
+}
+
+// terminates reports whether block ends with a return or panic.
+func terminates(block *ast.BlockStmt) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return last.Tok == token.CONTINUE || last.Tok == token.BREAK
+	case *ast.ExprStmt:
+		call, ok := last.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		return ok && ident.Name == "panic"
+	}
+	return false
+}
+
+// checkUnwrapped reports `return err` inside `if err != nil` when err came
+// from a call to a function in another package.
+func checkUnwrapped(pass *analysis.Pass, ret *ast.ReturnStmt, stack []ast.Node) {
+	ifStmt, prev := enclosingIf(stack)
+	if ifStmt == nil {
+		return
+	}
+	for _, result := range ret.Results {
+		ident, ok := ast.Unparen(result).(*ast.Ident)
+		if !ok {
+			continue
+		}
+		v := pass.TypesInfo.Uses[ident]
+		if v == nil || !isError(v.Type()) || !checksNil(pass.TypesInfo, ifStmt.Cond, v, token.NEQ) {
+			continue
+		}
+
+		assign := ifStmt.Init
+		if assign == nil {
+			assign = prev
+		}
+		fn := assignedFrom(pass.TypesInfo, assign, v)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg() == pass.Pkg || strings.HasPrefix(fn.Pkg().Path(), pkgPath) {
+			continue
+		}
+		pass.Reportf(ident.Pos(), "error returned from %s.%s is not wrapped; use e.Wrap(%s)",
+			fn.Pkg().Name(), fn.Name(), ident.Name)
+	}
+}
+
+// enclosingIf returns the if statement whose body directly contains the
+// innermost node of stack, along with the statement preceding the if
+// statement in its block (if any).
+func enclosingIf(stack []ast.Node) (*ast.IfStmt, ast.Stmt) {
+	if len(stack) < 4 {
+		return nil, nil
+	}
+	ifStmt, ok := stack[len(stack)-3].(*ast.IfStmt)
+	if !ok || stack[len(stack)-2] != ifStmt.Body {
+		return nil, nil
+	}
+	block, ok := stack[len(stack)-4].(*ast.BlockStmt)
+	if !ok {
+		return ifStmt, nil
+	}
+	for i, stmt := range block.List {
+		if stmt == ifStmt && i > 0 {
+			return ifStmt, block.List[i-1]
+		}
+	}
+	return ifStmt, nil
+}
+
+// assignedFrom returns the function called in stmt if stmt assigns its result
+// to v.
+func assignedFrom(info *types.Info, stmt ast.Stmt, v types.Object) *types.Func {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 {
+		return nil
+	}
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	for _, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if info.Defs[ident] == v || info.Uses[ident] == v {
+			return calledFunc(info, call)
+		}
+	}
+	return nil
+}
+
+// calledFunc returns the function or method called by call, or nil.
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[ident].(*types.Func)
+	return fn
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func isError(t types.Type) bool {
+	return types.Identical(t, errorType)
+}
@@ -0,0 +1,13 @@
+module github.com/kisunji/e/analyzer
+
+go 1.25.0
+
+require golang.org/x/tools v0.49.0
+
+require (
+	github.com/google/go-cmp v0.7.0 // indirect
+	golang.org/x/mod v0.40.0 // indirect
+	golang.org/x/sync v0.22.0 // indirect
+)
+
+replace github.com/kisunji/e => ../
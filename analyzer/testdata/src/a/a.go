@@ -0,0 +1,76 @@
+package a
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/kisunji/e"
+)
+
+func local() error { return nil }
+
+func Unwrapped() error {
+	_, err := os.Open("bar")
+	if err != nil {
+		return err // want `error returned from os.Open is not wrapped; use e.Wrap\(err\)`
+	}
+	if err := os.Remove("bar"); err != nil {
+		return err // want `error returned from os.Remove is not wrapped; use e.Wrap\(err\)`
+	}
+	return nil
+}
+
+func Wrapped() error {
+	_, err := os.Open("bar")
+	if err != nil {
+		return e.Wrap(err)
+	}
+	if err := local(); err != nil {
+		return err
+	}
+	if err := e.Wrap(os.ErrNotExist); err != nil {
+		return err
+	}
+	return nil
+}
+
+func Guarded(ctx context.Context) error {
+	err := local()
+	if err != nil {
+		return e.Wrap(err).SetCode("bar")
+	}
+	if err != nil && ctx != nil {
+		return e.WrapCtx(ctx, err).SetCode("bar")
+	}
+	if err == nil {
+		return nil
+	}
+	return e.Wrapf(err, "bar").SetCode("bar")
+}
+
+var ErrBar = e.NewError("bar", "sentinel") // want ErrBar:"nonNilVar"
+
+var errMaybe = local()
+
+func NonNil() error {
+	err := e.NewError("bar", "missing bar")
+	_ = e.Wrap(err).SetCode("bar")
+	_ = e.Wrap(ErrBar).SetCode("bar")
+	_ = e.Wrap(io.EOF).SetCode("bar")
+	return e.Wrap(errors.New("bar")).SetCode("bar")
+}
+
+func Unguarded(ctx context.Context) error {
+	err := local()
+	_ = e.Wrap(err).SetCode("bar")                                     // want `e.Wrap returns nil for a nil error; check err for nil before calling SetCode`
+	_ = e.WrapCtx(ctx, err).SetCode("bar")                             // want `e.WrapCtx returns nil for a nil error; check err for nil before calling SetCode`
+	_ = e.Wrap(errMaybe).SetCode("bar")                                // want `e.Wrap returns nil for a nil error; check errMaybe for nil before calling SetCode`
+	_ = e.Wrapw(err, "bar", 1).SetCode("bar")                          // want `e.Wrapw returns nil for a nil error; check err for nil before calling SetCode`
+	_ = e.WrapLazy(err, func() string { return "bar" }).SetCode("bar") // want `e.WrapLazy returns nil for a nil error; check err for nil before calling SetCode`
+	_ = e.ClearMessages(err).SetCode("bar")                            // want `e.ClearMessages returns nil for a nil error; check err for nil before calling SetCode`
+	_ = e.FromPkgErrors(err).SetCode("bar")                            // want `e.FromPkgErrors returns nil for a nil error; check err for nil before calling SetCode`
+	_ = e.Normalize(err).SetCode("bar")                                // want `e.Normalize returns nil for a nil error; check err for nil before calling SetCode`
+	return e.Wrap(local()).SetCode("bar")                              // want `e.Wrap returns nil for a nil error; check local\(\) for nil before calling SetCode`
+}
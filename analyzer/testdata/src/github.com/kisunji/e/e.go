@@ -0,0 +1,29 @@
+// Package e is a stub of github.com/kisunji/e for analyzer tests.
+package e
+
+import "context"
+
+type Error interface {
+	error
+	SetCode(code string) Error
+}
+
+func NewError(code, cause string) Error { return nil }
+
+func Wrap(err error, optionalInfo ...string) Error { return nil }
+
+func Wrapf(err error, fmtInfo string, args ...interface{}) Error { return nil }
+
+func Wrapw(err error, keysAndValues ...interface{}) Error { return nil }
+
+func WrapCtx(ctx context.Context, err error, optionalInfo ...string) Error { return nil }
+
+func WrapLazy(err error, info func() string) Error { return nil }
+
+func ClearMessages(err error) Error { return nil }
+
+func FromPkgErrors(err error) Error { return nil }
+
+func Normalize(err error) Error { return nil }
+
+func NewErrorf(code, fmtCause string, args ...interface{}) Error { return nil }
@@ -0,0 +1,74 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var (
+	benchErr    error
+	benchString string
+)
+
+func deepChain(depth int) error {
+	err := NewError(CodeInternal, "cannot foo")
+	for i := 0; i < depth; i++ {
+		err = Wrap(err, "bar")
+	}
+	return err
+}
+
+func BenchmarkNewError(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchErr = NewError(CodeInternal, "cannot foo")
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	base := errors.New("cannot foo")
+	b.Run("plain", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchErr = Wrap(base)
+		}
+	})
+	b.Run("info", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchErr = Wrap(base, "bar")
+		}
+	})
+	b.Run("error", func(b *testing.B) {
+		err := Wrap(base)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchErr = Wrap(err)
+		}
+	})
+}
+
+func BenchmarkError(b *testing.B) {
+	for _, depth := range []int{1, 10, 50} {
+		err := deepChain(depth)
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchString = err.Error()
+			}
+		})
+	}
+}
+
+func BenchmarkErrorCode(b *testing.B) {
+	for _, depth := range []int{1, 10, 50} {
+		err := deepChain(depth)
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchString = ErrorCode(err)
+			}
+		})
+	}
+}
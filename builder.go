@@ -0,0 +1,93 @@
+package e
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Builder collects the attributes of an Error before it is created, so that
+// errors with many attributes can be constructed in one readable expression,
+// or the same attributes can be reused for several errors. Builders are
+// immutable: every method returns a modified copy. The zero value is ready to
+// use.
+//
+// Usage:
+//
+//	return e.Build().
+//		Code(CodeDatabase).
+//		Message("Try again later").
+//		Field("table", table).
+//		Wrap(err, "query failed")
+type Builder struct {
+	code    string
+	setters []func(Error) Error
+}
+
+// Build returns an empty Builder.
+func Build() Builder {
+	return Builder{}
+}
+
+func (b Builder) with(setter func(Error) Error) Builder {
+	// clip so that copies of b never share appended setters
+	b.setters = append(slices.Clip(b.setters), setter)
+	return b
+}
+
+// Code sets the code of the built Error.
+func (b Builder) Code(code string) Builder {
+	b.code = code
+	return b
+}
+
+// Kind sets the kind of the built Error.
+func (b Builder) Kind(kind Kind) Builder {
+	return b.with(func(err Error) Error { return err.SetKind(kind) })
+}
+
+// Message sets the user-friendly message of the built Error.
+func (b Builder) Message(message string) Builder {
+	return b.with(func(err Error) Error { return err.SetMessage(message) })
+}
+
+// Retryable sets the retryability of the built Error.
+func (b Builder) Retryable(retryable bool) Builder {
+	return b.with(func(err Error) Error { return err.SetRetryable(retryable) })
+}
+
+// RetryAfter sets how long to wait before retrying the built Error.
+func (b Builder) RetryAfter(d time.Duration) Builder {
+	return b.with(func(err Error) Error { return err.SetRetryAfter(d) })
+}
+
+// Field adds a structured field to the built Error.
+func (b Builder) Field(key string, value interface{}) Builder {
+	return b.with(func(err Error) Error { return err.SetField(key, value) })
+}
+
+// New behaves like NewError with the attributes of b.
+func (b Builder) New(cause string) Error {
+	return runNewHooks(b.apply(newImpl(getCallingFunc(2), b.code, errors.New(cause))))
+}
+
+// Wrap behaves like Wrap with the attributes of b. Returns nil if err is nil.
+func (b Builder) Wrap(err error, optionalInfo ...string) Error {
+	if err == nil {
+		return nil
+	}
+
+	return wrap(err, firstInfo(optionalInfo), func(wrapped errorImpl) Error {
+		if b.code != "" {
+			wrapped.code = b.code
+		}
+		return b.apply(wrapped)
+	})
+}
+
+func (b Builder) apply(err Error) Error {
+	for _, setter := range b.setters {
+		err = setter(err)
+	}
+	return err
+}
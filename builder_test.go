@@ -0,0 +1,52 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := Build().Code(CodeDatabase).Message("Try again later").Field("table", "bars")
+
+	t.Run("New", func(t *testing.T) {
+		err := b.Kind(KindServer).New("cannot query bars")
+		if got, want := err.Error(), "TestBuilder.func1: [database_error] cannot query bars"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got := ErrorMessage(err); got != "Try again later" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "Try again later")
+		}
+		if got := ErrorKind(err); got != KindServer {
+			t.Errorf("\ngot:  %q\nwant: %q", got, KindServer)
+		}
+	})
+	t.Run("Wrap", func(t *testing.T) {
+		err := b.Retryable(true).Wrap(Foo(), "query failed")
+		if got, want := err.Error(), "TestBuilder.func2: [database_error] (query failed): Foo: [database_error] cannot foo"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got, want := ErrorFields(err), map[string]interface{}{"table": "bars"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+		if !IsRetryable(err) {
+			t.Errorf("expected built error to be retryable")
+		}
+		if err := b.Wrap(nil); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+	t.Run("immutable", func(t *testing.T) {
+		base := Build().Field("a", 1)
+		first := base.Field("b", 2)
+		second := base.Field("c", 3)
+		if got, want := ErrorFields(first.New("first")), map[string]interface{}{"a": 1, "b": 2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+		if got, want := ErrorFields(second.New("second")), map[string]interface{}{"a": 1, "c": 3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+		if got := ErrorCode(base.New("base")); got != "" {
+			t.Errorf("expected no code but got %q", got)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package e
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ItemError is the error of a single item of a bulk operation.
+type ItemError struct {
+	// Index of the item in the batch.
+	Index int
+	Err   error
+}
+
+func (i ItemError) Error() string {
+	return "item " + strconv.Itoa(i.Index) + ": " + i.Err.Error() // localizer.Ignore
+}
+
+func (i ItemError) Unwrap() error {
+	return i.Err
+}
+
+// ItemErrors is the cause of an Error created by WrapAll or a Collector.
+// errors.Is and errors.As inspect the error of every item.
+type ItemErrors []ItemError
+
+func (items ItemErrors) Error() string {
+	var sb strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(item.Error())
+	}
+	return sb.String()
+}
+
+func (items ItemErrors) Unwrap() []error {
+	errs := make([]error, len(items))
+	for i, item := range items {
+		errs[i] = item
+	}
+	return errs
+}
+
+// WrapAll joins the non-nil errors of a bulk operation into a single Error
+// with the name of the calling function. Every error keeps its own code and is
+// labeled with its index in errs. The Error has the code of the items if they
+// all have the same code. Returns nil if every error is nil.
+//
+// Use ErrorItems(err) to retrieve the errors of the items.
+//
+// Usage:
+//
+//	errs := make([]error, len(users))
+//	for i, u := range users {
+//		errs[i] = save(u)
+//	}
+//	return e.WrapAll(errs)
+func WrapAll(errs []error) Error {
+	var items ItemErrors
+	for i, err := range errs {
+		if err != nil {
+			items = append(items, ItemError{Index: i, Err: err})
+		}
+	}
+	return joinItems(getCallingFunc(2), items)
+}
+
+// joinItems constructs the Error created by WrapAll and Collector.
+func joinItems(op string, items ItemErrors) Error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	code := ErrorCode(items[0].Err)
+	for _, item := range items[1:] {
+		if ErrorCode(item.Err) != code {
+			code = ""
+			break
+		}
+	}
+	return runNewHooks(newImpl(op, code, &items))
+}
+
+// Collector aggregates the errors of a loop or batch job into a single Error
+// like WrapAll. The zero value is ready to use. A Collector must not be used
+// concurrently.
+//
+// Usage:
+//
+//	var c e.Collector
+//	for _, u := range users {
+//		c.Add(save(u))
+//	}
+//	return c.Err()
+type Collector struct {
+	items ItemErrors
+	n     int
+}
+
+// Add records the error of the next item. The index of the item counts every
+// call to Add, including calls with a nil error.
+func (c *Collector) Add(err error) {
+	if err != nil {
+		c.items = append(c.items, ItemError{Index: c.n, Err: err})
+	}
+	c.n++
+}
+
+// Err returns an Error with the name of the calling function joining the
+// errors added so far, or nil if none of them were non-nil.
+func (c *Collector) Err() Error {
+	items := make(ItemErrors, len(c.items))
+	copy(items, c.items)
+	return joinItems(getCallingFunc(2), items)
+}
+
+// ErrorItems returns the errors of the items joined by the first Error in the
+// chain created by WrapAll or a Collector. Otherwise returns nil.
+func ErrorItems(err error) ItemErrors {
+	for err := range chain(err) {
+		if items, ok := err.(*ItemErrors); ok {
+			return *items
+		}
+	}
+	return nil
+}
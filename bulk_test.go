@@ -0,0 +1,83 @@
+package e
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWrapAll(t *testing.T) {
+	errNotFound := errors.New("missing")
+	err := WrapAll([]error{
+		nil,
+		NewError(CodeInvalid, "bad email"),
+		nil,
+		Wrap(errNotFound).SetCode(CodeNotFound),
+	})
+
+	want := "TestWrapAll: item 1: TestWrapAll: [invalid] bad email; item 3: TestWrapAll: [not_found] missing"
+	if got := err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorCode(err); got != "" {
+		t.Errorf("expected no code for mixed items but got %q", got)
+	}
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("expected errors.Is to find the error of an item")
+	}
+
+	items := ErrorItems(Wrap(err))
+	var indices, codes []interface{}
+	for _, item := range items {
+		indices = append(indices, item.Index)
+		codes = append(codes, ErrorCode(item.Err))
+	}
+	if want := []interface{}{1, 3}; !reflect.DeepEqual(indices, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", indices, want)
+	}
+	if want := []interface{}{CodeInvalid, CodeNotFound}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", codes, want)
+	}
+
+	t.Run("same codes", func(t *testing.T) {
+		err := WrapAll([]error{Foo(), Foo()})
+		if got := ErrorCode(err); got != CodeDatabase {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeDatabase)
+		}
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		if err := WrapAll([]error{nil, nil}); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+}
+
+func TestCollector(t *testing.T) {
+	var c Collector
+	if err := c.Err(); err != nil {
+		t.Errorf("expected nil but got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if i%2 == 0 {
+			c.Add(nil)
+			continue
+		}
+		c.Add(Foo())
+	}
+	err := c.Err()
+
+	want := "TestCollector: [database_error] item 1: Foo: [database_error] cannot foo; item 3: Foo: [database_error] cannot foo"
+	if got := err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	c.Add(errors.New("late"))
+	if got := len(ErrorItems(err)); got != 2 {
+		t.Errorf("expected returned Error to not change but got %d items", got)
+	}
+	if got := ErrorItems(c.Err())[2].Index; got != 4 {
+		t.Errorf("\ngot:  %d\nwant: %d", got, 4)
+	}
+}
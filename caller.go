@@ -0,0 +1,113 @@
+package e
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var recordCallers atomic.Bool
+
+// SetCallers enables recording the file and line of every call to NewError,
+// Wrap and their variants. Unlike the stacktrace, which is captured once for
+// the root of an error chain, a single frame is recorded for every wrap site,
+// which is a lot cheaper. Use Callers to retrieve them; they are also printed
+// with the %+v verb.
+func SetCallers(enabled bool) {
+	recordCallers.Store(enabled)
+}
+
+// frame is the location of a single call. Only the program counter is
+// recorded when an error is created, unless the location was already
+// formatted by another process.
+type frame struct {
+	pc   uintptr
+	text string
+}
+
+// recordCaller records the frame of the caller skip frames above the caller of
+// recordCaller if enabled with SetCallers.
+func recordCaller(skip int) frame {
+	if !recordCallers.Load() {
+		return frame{}
+	}
+	var pcs [1]uintptr
+	// base offset is 2 to skip `runtime.Callers` and `recordCaller` itself
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return frame{}
+	}
+	return frame{pc: pcs[0]}
+}
+
+// String formats the frame as file:line.
+func (f frame) String() string {
+	if f.pc == 0 {
+		return f.text
+	}
+	fr, _ := runtime.CallersFrames([]uintptr{f.pc}).Next()
+	return fr.File + ":" + strconv.Itoa(fr.Line)
+}
+
+// Callers returns the file and line of every call to NewError and Wrap in the
+// error chain which were recorded with SetCallers, ordered from outermost to
+// innermost.
+func Callers(err error) []string {
+	var callers []string
+	for err := range chain(err) {
+		if e, ok := asImpl(err); ok {
+			if caller := e.caller.String(); caller != "" {
+				callers = append(callers, caller)
+			}
+		}
+	}
+	return callers
+}
+
+// Format implements fmt.Formatter. The %+v verb prints the full error string,
+// which is neither collapsed by MaxOps nor replaced in public mode, followed
+// by its hint, if any, and a single trace of the chain: the op and
+// caller of every wrap site recorded with SetCallers, outermost first,
+// followed by the stacktrace which is shared by the whole chain. The caller of
+// the innermost error is omitted if it is the first frame of the stacktrace.
+// Other verbs format the error string.
+func (e errorImpl) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+		return
+	}
+
+	f := currentFormatter()
+	f.MaxOps = 0
+	_, _ = io.WriteString(s, Format(e, f))
+	if hint := ErrorHint(e); hint != "" {
+		_, _ = io.WriteString(s, "\nhint: "+hint) // localizer.Ignore
+	}
+	var sites []string
+	for err := range chain(e) {
+		if impl, ok := asImpl(err); ok {
+			if caller := impl.caller.String(); caller != "" {
+				sites = append(sites, impl.op, caller)
+			}
+		}
+	}
+	stack := strings.TrimSuffix(ErrorStacktrace(e), "\n")
+	if n := len(sites); n > 0 && sites[n-1] == topFrame(stack) {
+		sites = sites[:n-2]
+	}
+	for i := 0; i < len(sites); i += 2 {
+		_, _ = io.WriteString(s, "\n"+sites[i]+"\n\t"+sites[i+1]) // localizer.Ignore
+	}
+	if stack != "" {
+		_, _ = io.WriteString(s, "\n"+stack)
+	}
+}
+
+// topFrame returns the file and line of the first frame of stack.
+func topFrame(stack string) string {
+	_, rest, _ := strings.Cut(stack, "\n\t")
+	location, _, _ := strings.Cut(rest, "\n")
+	return location
+}
@@ -0,0 +1,67 @@
+package e
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCallers(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		if got := Callers(Wrap(Foo())); got != nil {
+			t.Errorf("expected no callers but got %q", got)
+		}
+	})
+
+	SetCallers(true)
+	t.Cleanup(func() { SetCallers(false) })
+
+	err := Wrap(NewError(CodeNotFound, "cannot find bar"))
+	got := Callers(err)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 callers but got %q", got)
+	}
+	for _, caller := range got {
+		if !strings.Contains(caller, "caller_test.go:") {
+			t.Errorf("expected caller in caller_test.go but got %q", caller)
+		}
+	}
+
+	t.Run("printed with %+v", func(t *testing.T) {
+		// the caller of the root is the first frame of the stacktrace
+		want := err.Error() + "\nTestCallers\n\t" + got[0] + "\n" + strings.TrimSuffix(ErrorStacktrace(err), "\n")
+		if s := fmt.Sprintf("%+v", err); s != want {
+			t.Errorf("\ngot:  %q\nwant: %q", s, want)
+		}
+		if s := fmt.Sprintf("%v", err); s != err.Error() {
+			t.Errorf("\ngot:  %q\nwant: %q", s, err.Error())
+		}
+		if s, want := fmt.Sprintf("%q", err), fmt.Sprintf("%q", err.Error()); s != want {
+			t.Errorf("\ngot:  %s\nwant: %s", s, want)
+		}
+	})
+
+	t.Run("%+v prints the full chain", func(t *testing.T) {
+		SetPublicMode(true)
+		SetFormatter(Formatter{MaxOps: 1})
+		t.Cleanup(func() {
+			SetPublicMode(false)
+			SetFormatter(Formatter{})
+		})
+		want := "TestCallers.func4: Foo: [database_error] cannot foo"
+		if s, _, _ := strings.Cut(fmt.Sprintf("%+v", Wrap(Foo())), "\n"); s != want {
+			t.Errorf("\ngot:  %q\nwant: %q", s, want)
+		}
+	})
+
+	t.Run("survive Encode", func(t *testing.T) {
+		decoded, decodeErr := Decode(Encode(err))
+		if decodeErr != nil {
+			t.Fatal(decodeErr)
+		}
+		if callers := Callers(decoded); !reflect.DeepEqual(callers, got) {
+			t.Errorf("\ngot:  %q\nwant: %q", callers, got)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package e
+
+import (
+	"errors"
+	"iter"
+	"reflect"
+	"sync/atomic"
+)
+
+// DefaultMaxDepth is the number of errors visited when walking an error chain
+// unless changed with SetMaxDepth.
+const DefaultMaxDepth = 100
+
+var maxDepth atomic.Int64
+
+// SetMaxDepth limits how many errors in a chain are visited by functions such
+// as ErrorCode and Error(). Pathological chains deeper than the limit, or
+// chains where Unwrap forms a cycle, are truncated instead of hanging. Values
+// less than 1 restore DefaultMaxDepth.
+func SetMaxDepth(n int) {
+	maxDepth.Store(int64(n))
+}
+
+func depthLimit() int {
+	if n := maxDepth.Load(); n > 0 {
+		return int(n)
+	}
+	return DefaultMaxDepth
+}
+
+// chain iterates over err and the errors it wraps from outermost to innermost.
+// Iteration stops early after the depth limit or when an unwrap cycle is
+// detected.
+func chain(err error) iter.Seq[error] {
+	return chainAt(err, 0)
+}
+
+// chainAt behaves like chain for an error found depth errors down a chain.
+func chainAt(err error, depth int) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		walkAt(err, depth, yield)
+	}
+}
+
+// truncated reports whether chain(err) stops before the end of the chain.
+func truncated(err error) bool {
+	return !walk(err, func(error) bool { return true })
+}
+
+// walk calls fn for err and the errors it wraps until fn returns false.
+// Returns false if the chain was cut short by the depth limit or a cycle.
+//
+// Cycles are detected with Brent's algorithm so that no memory is allocated:
+// the error at every power of two steps is remembered and compared to the
+// following errors.
+func walk(err error, fn func(error) bool) bool {
+	return walkAt(err, 0, fn)
+}
+
+// walkAt behaves like walk for an error found depth errors down a chain.
+func walkAt(err error, depth int, fn func(error) bool) bool {
+	limit := depthLimit()
+	saved, power, steps := err, 1, 0
+	for ; err != nil; depth++ {
+		if depth == limit {
+			return false
+		}
+		if !fn(err) {
+			return true
+		}
+		err = errors.Unwrap(err)
+		if sameError(err, saved) {
+			return false
+		}
+		if steps++; steps == power {
+			saved, power, steps = err, power*2, 0
+		}
+	}
+	return true
+}
+
+// sameError reports whether a and b are the same pointer-like error. Other
+// errors are values which cannot form a cycle by themselves, and comparing
+// them could be expensive or panic.
+func sameError(a, b error) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	if _, ok := a.(interface{ impl() errorImpl }); ok {
+		return false
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return a == b
+	}
+	return false
+}
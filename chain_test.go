@@ -0,0 +1,71 @@
+package e
+
+import (
+	"strings"
+	"testing"
+)
+
+// cyclicError unwraps to next, which may point back to itself.
+type cyclicError struct {
+	next *cyclicError
+}
+
+func (c *cyclicError) Error() string {
+	return "cycle: " + c.next.Error()
+}
+
+func (c *cyclicError) Unwrap() error {
+	if c.next == nil {
+		return nil
+	}
+	return c.next
+}
+
+func TestCyclicChain(t *testing.T) {
+	for _, size := range []int{1, 2, 7} {
+		first := &cyclicError{}
+		last := first
+		for i := 1; i < size; i++ {
+			last.next = &cyclicError{}
+			last = last.next
+		}
+		last.next = first
+
+		err := Wrap(first).SetMessage("cannot foo")
+		if got := ErrorMessage(err); got != "cannot foo" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "cannot foo")
+		}
+		if got := ErrorCode(err); got != "" {
+			t.Errorf("expected blank code but got %q", got)
+		}
+		want := "TestCyclicChain: ..."
+		if got := err.Error(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	SetMaxDepth(3)
+	t.Cleanup(func() { SetMaxDepth(0) })
+
+	err := NewError(CodeInternal, "cannot foo")
+	for i := 0; i < 5; i++ {
+		err = Wrap(err)
+	}
+
+	if got := ErrorCode(err); got != "" {
+		t.Errorf("expected code beyond depth limit to be ignored but got %q", got)
+	}
+	if got := len(Ops(err)); got != 3 {
+		t.Errorf("\ngot:  %d\nwant: %d", got, 3)
+	}
+	if got := err.Error(); !strings.HasSuffix(got, "...") || strings.Count(got, "TestMaxDepth") != 3 {
+		t.Errorf("unexpected truncated error %q", got)
+	}
+
+	SetMaxDepth(0)
+	if got := ErrorCode(err); got != CodeInternal {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeInternal)
+	}
+}
@@ -0,0 +1,382 @@
+// Command errscatalog scans Go packages for error code constants and
+// e.RegisterCode calls and writes a catalog of the codes, their descriptions,
+// HTTP statuses and retryability as Markdown or JSON. The catalog can also be
+// written as a Go or TypeScript file of constants, so that client SDKs can
+// switch on the published codes without maintaining the list by hand.
+//
+// Constants are picked up if their name starts with "Code" and their value is
+// a string. The doc comment of a constant is used as its description unless a
+// description is registered with e.RegisterCode.
+//
+// Usage:
+//
+//	//go:generate go run github.com/kisunji/e/cmd/errscatalog -o ERRORS.md ./...
+//	//go:generate go run github.com/kisunji/e/cmd/errscatalog -format go -package errcodes -o sdk/errcodes/codes.go ./...
+//
+// Flags:
+//
+//	-format string
+//		output format, "markdown", "json", "go" or "typescript" (default "markdown")
+//	-o string
+//		output file (default stdout)
+//	-package string
+//		package name of the "go" format (default "errcodes")
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/types"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kisunji/e"
+	"golang.org/x/tools/go/packages"
+)
+
+// registerCode is the full name of the function whose calls are collected.
+const registerCode = "github.com/kisunji/e.RegisterCode"
+
+// entry is a single error code in the catalog.
+type entry struct {
+	Code        string `json:"code"`
+	Const       string `json:"const,omitempty"`
+	Description string `json:"description,omitempty"`
+	HTTPStatus  int    `json:"httpStatus"`
+	Retryable   bool   `json:"retryable"`
+}
+
+func main() {
+	format := flag.String("format", "markdown", `output format, "markdown", "json", "go" or "typescript"`)
+	out := flag.String("o", "", "output file (default stdout)")
+	pkgName := flag.String("package", "errcodes", `package name of the "go" format`)
+	flag.Parse()
+
+	if err := run(*format, *out, *pkgName, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "errscatalog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format, out, pkgName string, patterns []string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	var write func(io.Writer, []entry) error
+	switch format {
+	case "markdown":
+		write = writeMarkdown
+	case "json":
+		write = writeJSON
+	case "go":
+		write = func(w io.Writer, entries []entry) error {
+			return writeGo(w, pkgName, entries)
+		}
+	case "typescript":
+		write = writeTypeScript
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	entries, err := collect(patterns)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		return write(os.Stdout, entries)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := write(f, entries); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// collect loads the packages matching patterns and returns the catalog
+// entries sorted by code.
+func collect(patterns []string) ([]entry, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("failed to load packages")
+	}
+
+	c := collector{entries: make(map[string]*entry)}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			c.collectFile(pkg, file)
+		}
+	}
+
+	entries := make([]entry, 0, len(c.entries))
+	for _, ent := range c.entries {
+		entries = append(entries, *ent)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries, nil
+}
+
+type collector struct {
+	entries map[string]*entry
+}
+
+// get returns the entry for code, creating it with the default HTTP status
+// if it does not exist.
+func (c collector) get(code string) *entry {
+	ent, ok := c.entries[code]
+	if !ok {
+		ent = &entry{
+			Code:       code,
+			HTTPStatus: e.HTTPStatus(e.NewError(code, "")),
+		}
+		c.entries[code] = ent
+	}
+	return ent
+}
+
+func (c collector) collectFile(pkg *packages.Package, file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			doc := vs.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			if doc == nil {
+				doc = vs.Comment
+			}
+			for _, name := range vs.Names {
+				obj, ok := pkg.TypesInfo.Defs[name].(*types.Const)
+				if !ok || !strings.HasPrefix(name.Name, "Code") || obj.Val().Kind() != constant.String {
+					continue
+				}
+				ent := c.get(constant.StringVal(obj.Val()))
+				ent.Const = pkg.Name + "." + name.Name
+				if ent.Description == "" && doc != nil {
+					ent.Description = strings.Join(strings.Fields(doc.Text()), " ")
+				}
+			}
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 || !isRegisterCode(pkg.TypesInfo, call.Fun) {
+			return true
+		}
+		code, ok := stringValue(pkg.TypesInfo, call.Args[0])
+		if !ok {
+			return true
+		}
+		ent := c.get(code)
+		lit, ok := call.Args[1].(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			val := pkg.TypesInfo.Types[kv.Value].Value
+			if val == nil {
+				continue
+			}
+			switch {
+			case key.Name == "Description" && val.Kind() == constant.String:
+				ent.Description = strings.Join(strings.Fields(constant.StringVal(val)), " ")
+			case key.Name == "HTTPStatus" && val.Kind() == constant.Int:
+				if status, ok := constant.Int64Val(val); ok && status != 0 {
+					ent.HTTPStatus = int(status)
+				}
+			case key.Name == "Retryable" && val.Kind() == constant.Bool:
+				ent.Retryable = constant.BoolVal(val)
+			}
+		}
+		return true
+	})
+}
+
+// isRegisterCode reports whether fun refers to e.RegisterCode.
+func isRegisterCode(info *types.Info, fun ast.Expr) bool {
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return false
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	return ok && fn.FullName() == registerCode
+}
+
+// stringValue returns the value of expr if it is a constant string.
+func stringValue(info *types.Info, expr ast.Expr) (string, bool) {
+	val := info.Types[expr].Value
+	if val == nil || val.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(val), true
+}
+
+func writeMarkdown(w io.Writer, entries []entry) error {
+	var sb strings.Builder
+	sb.WriteString("# Error codes\n\n")
+	sb.WriteString("| Code | Description | HTTP status | Retryable |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, ent := range entries {
+		desc := strings.ReplaceAll(ent.Description, "|", `\|`)
+		fmt.Fprintf(&sb, "| `%s` | %s | %d | %t |\n", ent.Code, desc, ent.HTTPStatus, ent.Retryable)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeJSON(w io.Writer, entries []entry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// generatedHeader marks generated files for tools such as linters.
+const generatedHeader = "// Code generated by errscatalog. DO NOT EDIT.\n\n"
+
+func writeGo(w io.Writer, pkgName string, entries []entry) error {
+	names, err := constNames(entries)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(generatedHeader)
+	fmt.Fprintf(&sb, "// Package %s lists the published error codes.\n", pkgName)
+	fmt.Fprintf(&sb, "package %s\n\nconst (\n", pkgName)
+	for i, ent := range entries {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, line := range docLines(ent, names[i]) {
+			sb.WriteString(strings.TrimSpace("// "+line) + "\n")
+		}
+		fmt.Fprintf(&sb, "%s = %s\n", names[i], strconv.Quote(ent.Code))
+	}
+	sb.WriteString(")\n")
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func writeTypeScript(w io.Writer, entries []entry) error {
+	names, err := constNames(entries)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(generatedHeader)
+	types := make([]string, len(entries))
+	for i, ent := range entries {
+		types[i] = "typeof " + names[i]
+		sb.WriteString("/**\n")
+		for _, line := range docLines(ent, names[i]) {
+			sb.WriteString(strings.TrimRight(" * "+strings.ReplaceAll(line, "*/", `*\/`), " ") + "\n")
+		}
+		code, _ := json.Marshal(ent.Code)
+		fmt.Fprintf(&sb, " */\nexport const %s = %s;\n\n", names[i], code)
+	}
+	if len(types) == 0 {
+		types = append(types, "never")
+	}
+	fmt.Fprintf(&sb, "export type ErrorCode = %s;\n", strings.Join(types, " | "))
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// docLines returns the doc comment of the constant of ent named name.
+func docLines(ent entry, name string) []string {
+	lines := []string{name + " is " + strconv.Quote(ent.Code) + "."}
+	if ent.Description != "" {
+		lines[0] = ent.Description
+	}
+	status := "HTTP status " + strconv.Itoa(ent.HTTPStatus)
+	if ent.Retryable {
+		status += ", retryable"
+	}
+	return append(lines, "", status+".")
+}
+
+// constNames returns the names of the constants of entries. Returns an error
+// if two codes map to the same name, e.g. "quota.exceeded" and
+// "quota_exceeded", since the generated file would not compile.
+func constNames(entries []entry) ([]string, error) {
+	names := make([]string, len(entries))
+	codes := make(map[string]string, len(entries))
+	for i, ent := range entries {
+		names[i] = constName(ent)
+		if code, ok := codes[names[i]]; ok {
+			return nil, fmt.Errorf("codes %q and %q both map to constant %s", code, ent.Code, names[i])
+		}
+		codes[names[i]] = ent.Code
+	}
+	return names, nil
+}
+
+// constName returns the name of the constant of ent in the catalog, which is
+// the name of its constant in the scanned packages if there is one, or the
+// code in camel case prefixed with "Code" otherwise.
+func constName(ent entry) string {
+	if _, name, ok := strings.Cut(ent.Const, "."); ok {
+		return name
+	}
+	var sb strings.Builder
+	sb.WriteString("Code")
+	upper := true
+	for _, r := range ent.Code {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+		}
+		sb.WriteRune(r)
+		upper = false
+	}
+	return sb.String()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	entries, err := collect([]string{"./testdata/codes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []entry{
+		{
+			Code:        "flaky_upstream",
+			Description: "An upstream service | failed.",
+			HTTPStatus:  500,
+			Retryable:   true,
+		},
+		{
+			Code:        "not_found",
+			Const:       "codes.CodeMissingBar",
+			Description: "CodeMissingBar is returned when a bar cannot be found.",
+			HTTPStatus:  404,
+		},
+		{
+			Code:        "quota_exceeded",
+			Const:       "codes.CodeQuotaExceeded",
+			Description: "CodeQuotaExceeded means the tenant has used up its request quota.",
+			HTTPStatus:  429,
+			Retryable:   true,
+		},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", entries, want)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeMarkdown(&buf, []entry{
+		{Code: "flaky_upstream", Description: "An upstream service | failed.", HTTPStatus: 500, Retryable: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Error codes\n\n" +
+		"| Code | Description | HTTP status | Retryable |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| `flaky_upstream` | An upstream service \\| failed. | 500 | true |\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []entry{{Code: "quota_exceeded", HTTPStatus: 429, Retryable: true}}
+	if err := writeJSON(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	var got []entry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, entries)
+	}
+}
+
+func TestWriteGo(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeGo(&buf, "errcodes", []entry{
+		{Code: "flaky_upstream", Description: "An upstream service failed.", HTTPStatus: 500, Retryable: true},
+		{Code: "not_found", Const: "codes.CodeMissingBar", HTTPStatus: 404},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Code generated by errscatalog. DO NOT EDIT.\n\n" +
+		"// Package errcodes lists the published error codes.\n" +
+		"package errcodes\n\n" +
+		"const (\n" +
+		"\t// An upstream service failed.\n" +
+		"\t//\n" +
+		"\t// HTTP status 500, retryable.\n" +
+		"\tCodeFlakyUpstream = \"flaky_upstream\"\n\n" +
+		"\t// CodeMissingBar is \"not_found\".\n" +
+		"\t//\n" +
+		"\t// HTTP status 404.\n" +
+		"\tCodeMissingBar = \"not_found\"\n" +
+		")\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestWriteTypeScript(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeTypeScript(&buf, []entry{
+		{Code: "quota.exceeded", Description: "Ends comments */ early.", HTTPStatus: 429, Retryable: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Code generated by errscatalog. DO NOT EDIT.\n\n" +
+		"/**\n" +
+		" * Ends comments *\\/ early.\n" +
+		" *\n" +
+		" * HTTP status 429, retryable.\n" +
+		" */\n" +
+		"export const CodeQuotaExceeded = \"quota.exceeded\";\n\n" +
+		"export type ErrorCode = typeof CodeQuotaExceeded;\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestConstNameCollision(t *testing.T) {
+	entries := []entry{
+		{Code: "quota.exceeded", HTTPStatus: 429},
+		{Code: "quota_exceeded", HTTPStatus: 429},
+	}
+	want := `codes "quota.exceeded" and "quota_exceeded" both map to constant CodeQuotaExceeded`
+	if err := writeGo(&bytes.Buffer{}, "errcodes", entries); err == nil || err.Error() != want {
+		t.Errorf("\ngot:  %v\nwant: %s", err, want)
+	}
+	if err := writeTypeScript(&bytes.Buffer{}, entries); err == nil || err.Error() != want {
+		t.Errorf("\ngot:  %v\nwant: %s", err, want)
+	}
+}
@@ -0,0 +1,33 @@
+package codes
+
+import (
+	"net/http"
+
+	"github.com/kisunji/e"
+)
+
+const (
+	// CodeQuotaExceeded means the tenant has used up its request quota.
+	CodeQuotaExceeded = "quota_exceeded"
+
+	// CodeMissingBar is returned when a bar cannot be found.
+	CodeMissingBar = e.CodeNotFound
+
+	// codeUnexported is not part of the catalog.
+	codeUnexported = "unexported"
+
+	// MaxBars is not a code.
+	MaxBars = 3
+)
+
+func init() {
+	e.RegisterCode(CodeQuotaExceeded, e.CodeInfo{
+		HTTPStatus: http.StatusTooManyRequests,
+		Retryable:  true,
+	})
+	e.RegisterCode("flaky_upstream", e.CodeInfo{
+		Description: `An upstream service |
+			failed.`,
+		Retryable: true,
+	})
+}
@@ -0,0 +1,16 @@
+// Command errsvet runs the checks of package analyzer.
+//
+// Usage:
+//
+//	errsvet ./...
+//	go vet -vettool=$(which errsvet) ./...
+package main
+
+import (
+	"github.com/kisunji/e/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,19 @@
+module github.com/kisunji/e/cmd
+
+go 1.25.0
+
+require (
+	github.com/kisunji/e v0.0.0-00010101000000-000000000000
+	github.com/kisunji/e/analyzer v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.49.0
+)
+
+require (
+	golang.org/x/mod v0.40.0 // indirect
+	golang.org/x/sync v0.22.0 // indirect
+)
+
+replace (
+	github.com/kisunji/e => ../
+	github.com/kisunji/e/analyzer => ../analyzer
+)
@@ -0,0 +1,174 @@
+package e
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Canonical codes which cover the most common failure types. Applications are
+// free to define their own codes alongside these.
+const (
+	CodeNotFound    = "not_found"
+	CodeInvalid     = "invalid"
+	CodePermission  = "permission_denied"
+	CodeTimeout     = "timeout"
+	CodeCanceled    = "canceled"
+	CodeConflict    = "conflict"
+	CodeUnavailable = "unavailable"
+	CodeRateLimited = "rate_limited"
+)
+
+// CodeSeparator separates the segments of hierarchical codes such as
+// "storage.postgres.unique_violation", which let teams namespace their codes.
+const CodeSeparator = "."
+
+// CodeHasPrefix reports whether any code in the chain of err is prefix or is
+// namespaced under prefix, so that handlers can match hierarchical codes at
+// any granularity. Only whole segments match: "storage" matches
+// "storage.postgres.unique_violation" but not "storage_error".
+//
+// Usage:
+//
+//	switch {
+//	case e.CodeHasPrefix(err, "storage.postgres.unique_violation"):
+//		return http.StatusConflict
+//	case e.CodeHasPrefix(err, "storage"):
+//		return http.StatusServiceUnavailable
+//	}
+func CodeHasPrefix(err error, prefix string) bool {
+	for err := range chain(err) {
+		if codeHasPrefix(codeOf(err), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func codeHasPrefix(code, prefix string) bool {
+	if !strings.HasPrefix(code, prefix) || prefix == "" {
+		return false
+	}
+	return len(code) == len(prefix) || strings.HasPrefix(code[len(prefix):], CodeSeparator)
+}
+
+// parentCode returns code without its last segment, or an empty string if
+// code is not namespaced.
+func parentCode(code string) string {
+	i := strings.LastIndex(code, CodeSeparator)
+	if i < 0 {
+		return ""
+	}
+	return code[:i]
+}
+
+// Classify maps well-known errors from the standard library onto one of the
+// canonical codes. Returns an empty string if err is not recognized.
+//
+// Wrap and Wrapf use Classify to assign a code when the wrapped error
+// does not already have one.
+func Classify(err error) string {
+	// errors.Is and errors.As do not terminate on cyclic chains.
+	if err == nil || truncated(err) {
+		return ""
+	}
+	return classify(err)
+}
+
+// classify implements Classify for non-nil errors with a finite chain.
+func classify(err error) string {
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case isTimeout(err):
+		return CodeTimeout
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, sql.ErrNoRows):
+		return CodeNotFound
+	case errors.Is(err, os.ErrExist):
+		return CodeConflict
+	case errors.Is(err, os.ErrPermission):
+		return CodePermission
+	case errors.Is(err, os.ErrInvalid):
+		return CodeInvalid
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return CodeUnavailable
+	}
+	return ""
+}
+
+// IsCanceled returns true if err has CodeCanceled or context.Canceled is
+// anywhere in the chain.
+func IsCanceled(err error) bool {
+	return ErrorCode(err) == CodeCanceled || errors.Is(err, context.Canceled)
+}
+
+// IsTimeout returns true if err has CodeTimeout, or context.DeadlineExceeded
+// or a net.Error timeout is anywhere in the chain.
+func IsTimeout(err error) bool {
+	return ErrorCode(err) == CodeTimeout || isTimeout(err)
+}
+
+// IsNotFound returns true if err has CodeNotFound, or os.ErrNotExist or
+// sql.ErrNoRows is anywhere in the chain.
+func IsNotFound(err error) bool {
+	return ErrorCode(err) == CodeNotFound || errors.Is(err, os.ErrNotExist) || errors.Is(err, sql.ErrNoRows)
+}
+
+// IsInvalid returns true if err has CodeInvalid or CodeValidation, or
+// os.ErrInvalid is anywhere in the chain.
+func IsInvalid(err error) bool {
+	code := ErrorCode(err)
+	return code == CodeInvalid || code == CodeValidation || errors.Is(err, os.ErrInvalid)
+}
+
+// IsPermission returns true if err has CodePermission or os.ErrPermission is
+// anywhere in the chain.
+func IsPermission(err error) bool {
+	return ErrorCode(err) == CodePermission || errors.Is(err, os.ErrPermission)
+}
+
+// IsConflict returns true if err has CodeConflict or os.ErrExist is anywhere
+// in the chain.
+func IsConflict(err error) bool {
+	return ErrorCode(err) == CodeConflict || errors.Is(err, os.ErrExist)
+}
+
+// IsUnavailable returns true if err has CodeUnavailable or a refused
+// connection is anywhere in the chain.
+func IsUnavailable(err error) bool {
+	return ErrorCode(err) == CodeUnavailable || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsRateLimited returns true if err has CodeRateLimited.
+func IsRateLimited(err error) bool {
+	return ErrorCode(err) == CodeRateLimited
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	netErr, ok := asNetError(err)
+	return ok && netErr.Timeout()
+}
+
+// asNetError behaves like errors.As for net.Error. It avoids the allocation of
+// errors.As for chains which only use single-error Unwrap.
+func asNetError(err error) (net.Error, bool) {
+	for err := range chain(err) {
+		switch x := err.(type) {
+		case net.Error:
+			return x, true
+		case interface{ As(interface{}) bool }, interface{ Unwrap() []error }:
+			var netErr net.Error
+			ok := errors.As(err, &netErr)
+			return netErr, ok
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,219 @@
+package e
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nil returns blank",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "unknown error returns blank",
+			err:  errors.New("unknown"),
+			want: "",
+		},
+		{
+			name: "os.ErrNotExist is not found",
+			err:  os.ErrNotExist,
+			want: CodeNotFound,
+		},
+		{
+			name: "sql.ErrNoRows is not found",
+			err:  sql.ErrNoRows,
+			want: CodeNotFound,
+		},
+		{
+			name: "os.ErrPermission is permission denied",
+			err:  os.ErrPermission,
+			want: CodePermission,
+		},
+		{
+			name: "context.DeadlineExceeded is timeout",
+			err:  context.DeadlineExceeded,
+			want: CodeTimeout,
+		},
+		{
+			name: "context.Canceled is canceled",
+			err:  context.Canceled,
+			want: CodeCanceled,
+		},
+		{
+			name: "net timeout is timeout",
+			err:  timeoutError{},
+			want: CodeTimeout,
+		},
+		{
+			name: "works with non-pkg wrapping",
+			err:  fmt.Errorf("open config: %w", os.ErrNotExist),
+			want: CodeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapClassifies(t *testing.T) {
+	t.Run("wrap assigns canonical code", func(t *testing.T) {
+		err := Wrap(sql.ErrNoRows)
+		if got := ErrorCode(err); got != CodeNotFound {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeNotFound)
+		}
+	})
+	t.Run("wrap keeps existing code", func(t *testing.T) {
+		err := Wrap(Wrap(sql.ErrNoRows).SetCode(CodeDatabase))
+		if got := ErrorCode(err); got != CodeDatabase {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeDatabase)
+		}
+	})
+}
+
+func TestIsCanceled(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "unrelated error",
+			err:  Foo(),
+			want: false,
+		},
+		{
+			name: "context.Canceled deep in the chain",
+			err:  Wrap(fmt.Errorf("query: %w", context.Canceled)).SetCode(CodeDatabase),
+			want: true,
+		},
+		{
+			name: "canceled code",
+			err:  NewError(CodeCanceled, "user went away"),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCanceled(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "unrelated error",
+			err:  Foo(),
+			want: false,
+		},
+		{
+			name: "context.DeadlineExceeded deep in the chain",
+			err:  Wrap(fmt.Errorf("query: %w", context.DeadlineExceeded)).SetCode(CodeDatabase),
+			want: true,
+		},
+		{
+			name: "net timeout",
+			err:  Wrap(timeoutError{}),
+			want: true,
+		},
+		{
+			name: "timeout code",
+			err:  NewError(CodeTimeout, "too slow"),
+			want: true,
+		},
+		{
+			name: "net timeout in joined errors",
+			err:  Wrap(errors.Join(errors.New("retry failed"), timeoutError{})),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTimeout(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeHasPrefix(t *testing.T) {
+	const code = "storage.postgres.unique_violation"
+	err := Wrap(NewError(code, "duplicate key")).SetCode(CodeInternal)
+
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{prefix: "storage", want: true},
+		{prefix: "storage.postgres", want: true},
+		{prefix: code, want: true},
+		{prefix: CodeInternal, want: true},
+		{prefix: "stor", want: false},
+		{prefix: "storage.postgres.unique", want: false},
+		{prefix: "postgres", want: false},
+		{prefix: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			if got := CodeHasPrefix(err, tt.prefix); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		name string
+		is   func(error) bool
+		err  error
+		want bool
+	}{
+		{"IsNotFound code", IsNotFound, NewError(CodeNotFound, "cannot find bar"), true},
+		{"IsNotFound sentinel", IsNotFound, fmt.Errorf("scan: %w", sql.ErrNoRows), true},
+		{"IsNotFound unrelated", IsNotFound, Foo(), false},
+		{"IsInvalid validation", IsInvalid, NewValidation().AddField("name", "is required"), true},
+		{"IsInvalid sentinel", IsInvalid, Wrap(os.ErrInvalid).SetCode(CodeDatabase), true},
+		{"IsPermission sentinel", IsPermission, Wrap(os.ErrPermission), true},
+		{"IsConflict code", IsConflict, NewError(CodeConflict, "bar exists"), true},
+		{"IsConflict nil", IsConflict, nil, false},
+		{"IsUnavailable sentinel", IsUnavailable, fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"IsRateLimited code", IsRateLimited, Wrap(NewError(CodeRateLimited, "slow down")), true},
+		{"IsRateLimited unrelated", IsRateLimited, Foo(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.is(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package e
+
+// WrapIf behaves like Wrap if cond is true and returns err unchanged
+// otherwise, e.g. for middleware which should only add its op to errors from
+// some routes. Returns nil if err is nil.
+//
+// Usage:
+//
+//	return e.WrapIf(verbose, err, "request failed")
+func WrapIf(cond bool, err error, optionalInfo ...string) error {
+	if err == nil {
+		return nil
+	}
+	if !cond {
+		return err
+	}
+
+	return wrap(err, firstInfo(optionalInfo), nil)
+}
+
+// WrapUnlessCode behaves like Wrap unless the code of err is one of codes, in
+// which case err is returned unchanged. This avoids wrapping errors which
+// already carry a terminal code again in every layer, e.g. a CodeNotFound
+// returned by a repository to a service. Codes match like in ExpectCode, so
+// aliases and namespaces are honored. Returns nil if err is nil.
+//
+// Usage:
+//
+//	return e.WrapUnlessCode(err, e.CodeNotFound, e.CodeInvalid)
+func WrapUnlessCode(err error, codes ...string) error {
+	if err == nil {
+		return nil
+	}
+	if isExpected(ErrorCode(err), codes) {
+		return err
+	}
+
+	return wrap(err, "", nil)
+}
@@ -0,0 +1,71 @@
+package e
+
+import "testing"
+
+func TestWrapIf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "wrapped",
+			err:  WrapIf(true, Foo(), "bar id: 1"),
+			want: "TestWrapIf: (bar id: 1): Foo: [database_error] cannot foo",
+		},
+		{
+			name: "unchanged",
+			err:  WrapIf(false, Foo(), "bar id: 1"),
+			want: "Foo: [database_error] cannot foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+	if err := WrapIf(true, nil); err != nil {
+		t.Errorf("expected WrapIf of nil error to return nil but got %#v", err)
+	}
+}
+
+func TestWrapUnlessCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "terminal code",
+			err:  WrapUnlessCode(NewError(CodeNotFound, "cannot find bar"), CodeInvalid, CodeNotFound),
+			want: "TestWrapUnlessCode: [not_found] cannot find bar",
+		},
+		{
+			name: "other code",
+			err:  WrapUnlessCode(Foo(), CodeNotFound),
+			want: "TestWrapUnlessCode: Foo: [database_error] cannot foo",
+		},
+		{
+			name: "namespace",
+			err:  WrapUnlessCode(NewError("storage.postgres.timeout", "cannot reach bar"), "storage"),
+			want: "TestWrapUnlessCode: [storage.postgres.timeout] cannot reach bar",
+		},
+		{
+			name: "no codes",
+			err:  WrapUnlessCode(Foo()),
+			want: "TestWrapUnlessCode: Foo: [database_error] cannot foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+	if err := WrapUnlessCode(nil, CodeNotFound); err != nil {
+		t.Errorf("expected WrapUnlessCode of nil error to return nil but got %#v", err)
+	}
+}
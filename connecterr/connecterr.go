@@ -0,0 +1,139 @@
+// Package connecterr converts errors from package e to and from connect-go
+// errors. The connect code is mapped from the canonical codes and the original
+// code, id and retryability are carried in error metadata so they round-trip.
+package connecterr
+
+import (
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/kisunji/e"
+)
+
+// Metadata keys used to carry the error across the connection. They are the
+// headers used by e.EncodeHeaders.
+const (
+	MetaCode      = e.HeaderCode
+	MetaID        = e.HeaderID
+	MetaRetryable = e.HeaderRetryable
+)
+
+var toConnect = map[string]connect.Code{
+	e.CodeNotFound:    connect.CodeNotFound,
+	e.CodeInvalid:     connect.CodeInvalidArgument,
+	e.CodeValidation:  connect.CodeInvalidArgument,
+	e.CodePermission:  connect.CodePermissionDenied,
+	e.CodeTimeout:     connect.CodeDeadlineExceeded,
+	e.CodeCanceled:    connect.CodeCanceled,
+	e.CodeConflict:    connect.CodeAborted,
+	e.CodeUnavailable: connect.CodeUnavailable,
+	e.CodeRateLimited: connect.CodeResourceExhausted,
+}
+
+var fromConnect = map[connect.Code]string{
+	connect.CodeNotFound:          e.CodeNotFound,
+	connect.CodeInvalidArgument:   e.CodeInvalid,
+	connect.CodePermissionDenied:  e.CodePermission,
+	connect.CodeDeadlineExceeded:  e.CodeTimeout,
+	connect.CodeCanceled:          e.CodeCanceled,
+	connect.CodeAborted:           e.CodeConflict,
+	connect.CodeAlreadyExists:     e.CodeConflict,
+	connect.CodeUnavailable:       e.CodeUnavailable,
+	connect.CodeResourceExhausted: e.CodeRateLimited,
+}
+
+// ToConnect converts err into a *connect.Error with the GRPCCode of the
+// e.ErrorCodeInfo of err, or the connect code matching its code. Only the
+// client-facing parts of err are sent: the message is e.ErrorMessage(err).
+// Errors which already are connect errors and carry no code are returned
+// as-is.
+func ToConnect(err error) *connect.Error {
+	if err == nil {
+		return nil
+	}
+
+	code := e.ErrorCode(err)
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) && code == "" {
+		return connectErr
+	}
+
+	connectCode, ok := toConnect[code]
+	// connect codes share their values with gRPC codes
+	if info, found := e.ErrorCodeInfo(err); found && info.GRPCCode != 0 {
+		connectCode, ok = connect.Code(info.GRPCCode), true
+	}
+	if !ok {
+		connectCode = connect.CodeUnknown
+	}
+	converted := connect.NewError(connectCode, errors.New(e.ErrorMessage(err)))
+	if code != "" {
+		converted.Meta().Set(MetaCode, code)
+	}
+	if id := e.ErrorID(err); id != "" {
+		converted.Meta().Set(MetaID, id)
+	}
+	if e.IsRetryable(err) {
+		converted.Meta().Set(MetaRetryable, "true")
+	}
+	return converted
+}
+
+// FromConnect converts err into an error compatible with package e if it is a
+// *connect.Error. The code is taken from metadata set by ToConnect, or mapped
+// from the connect code onto a canonical code otherwise. Other errors are
+// returned unchanged.
+func FromConnect(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return err
+	}
+
+	remote := remoteError{
+		code:    fromConnect[connectErr.Code()],
+		connect: connectErr,
+	}
+	if code := connectErr.Meta().Get(MetaCode); code != "" {
+		remote.code = code
+	}
+	remote.id = connectErr.Meta().Get(MetaID)
+	remote.retryable = connectErr.Meta().Get(MetaRetryable) == "true"
+	return remote
+}
+
+// remoteError implements e.ClientFacing, e.Retrier and e.HasID so it can be
+// introspected with e.ErrorCode, e.ErrorMessage, e.IsRetryable and e.ErrorID.
+type remoteError struct {
+	code      string
+	id        string
+	retryable bool
+	connect   *connect.Error
+}
+
+func (r remoteError) Error() string {
+	if r.code == "" {
+		return r.connect.Error()
+	}
+	return "[" + r.code + "] " + r.connect.Error() // localizer.Ignore
+}
+
+// Unwrap allows connect.CodeOf and errors.As to be used with converted errors.
+func (r remoteError) Unwrap() error {
+	return r.connect
+}
+
+func (r remoteError) ClientCode() string {
+	return r.code
+}
+
+func (r remoteError) ClientMessage() string {
+	return r.connect.Message()
+}
+
+func (r remoteError) Retryable() bool {
+	return r.retryable
+}
+
+func (r remoteError) ID() string {
+	return r.id
+}
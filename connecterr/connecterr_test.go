@@ -0,0 +1,68 @@
+package connecterr
+
+import (
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/kisunji/e"
+)
+
+func TestRoundTrip(t *testing.T) {
+	err := e.NewError("quota_exceeded", "too many bars for tenant 12").
+		SetMessage("Please slow down").
+		SetID("abc123").
+		SetRetryable(true)
+
+	sent := ToConnect(err)
+	if sent.Code() != connect.CodeUnknown {
+		t.Errorf("\ngot:  %v\nwant: %v", sent.Code(), connect.CodeUnknown)
+	}
+	if sent.Message() != "Please slow down" {
+		t.Errorf("expected only client message to be sent but got %q", sent.Message())
+	}
+
+	received := FromConnect(sent)
+	if got := e.ErrorCode(received); got != "quota_exceeded" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "quota_exceeded")
+	}
+	if got := e.ErrorMessage(received); got != "Please slow down" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "Please slow down")
+	}
+	if got := e.ErrorID(received); got != "abc123" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "abc123")
+	}
+	if !e.IsRetryable(received) {
+		t.Errorf("expected received error to be retryable")
+	}
+	if connect.CodeOf(received) != connect.CodeUnknown {
+		t.Errorf("expected received error to remain a connect error")
+	}
+}
+
+func TestCanonicalCodes(t *testing.T) {
+	if got := ToConnect(e.NewError(e.CodeNotFound, "no bar")).Code(); got != connect.CodeNotFound {
+		t.Errorf("\ngot:  %v\nwant: %v", got, connect.CodeNotFound)
+	}
+	foreign := connect.NewError(connect.CodeUnavailable, errors.New("down"))
+	if got := e.ErrorCode(FromConnect(foreign)); got != e.CodeUnavailable {
+		t.Errorf("\ngot:  %q\nwant: %q", got, e.CodeUnavailable)
+	}
+	if got := ToConnect(foreign); got != foreign {
+		t.Errorf("expected existing connect error to be passed through")
+	}
+}
+
+func TestRegisteredGRPCCode(t *testing.T) {
+	e.RegisterCode("tenant_suspended", e.CodeInfo{GRPCCode: uint32(connect.CodeFailedPrecondition)})
+	if got := ToConnect(e.NewError("tenant_suspended", "tenant 12 is suspended")).Code(); got != connect.CodeFailedPrecondition {
+		t.Errorf("\ngot:  %v\nwant: %v", got, connect.CodeFailedPrecondition)
+	}
+}
+
+func TestFromConnectNonConnectError(t *testing.T) {
+	err := errors.New("boom")
+	if got := FromConnect(err); got != err {
+		t.Errorf("expected %v but got %v", err, got)
+	}
+}
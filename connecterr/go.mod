@@ -0,0 +1,12 @@
+module github.com/kisunji/e/connecterr
+
+go 1.25.0
+
+require (
+	connectrpc.com/connect v1.21.0
+	github.com/kisunji/e v0.0.0-00010101000000-000000000000
+)
+
+require google.golang.org/protobuf v1.36.12 // indirect
+
+replace github.com/kisunji/e => ../
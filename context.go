@@ -0,0 +1,78 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []func(ctx context.Context) map[string]interface{}
+)
+
+// RegisterContextExtractor registers fn to pull values such as a trace id,
+// request id or tenant out of a context. The values are attached as fields by
+// NewCtx and WrapCtx. Extractors registered later override fields with the
+// same key.
+//
+// Usage:
+//
+//	func init() {
+//		e.RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+//			if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+//				return map[string]interface{}{"request_id": id}
+//			}
+//			return nil
+//		})
+//	}
+func RegisterContextExtractor(fn func(ctx context.Context) map[string]interface{}) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// NewCtx behaves like NewError and attaches the fields extracted from ctx by
+// registered context extractors.
+func NewCtx(ctx context.Context, code, cause string) Error {
+	created := newImpl(getCallingFunc(2), code, errors.New(cause))
+	if created.stack != unsampledStack {
+		created.fields = fieldsRef(contextFields(ctx))
+	}
+	return runNewHooks(created)
+}
+
+// WrapCtx behaves like Wrap and attaches the fields extracted from ctx by
+// registered context extractors. Returns nil if err is nil.
+func WrapCtx(ctx context.Context, err error, optionalInfo ...string) Error {
+	if err == nil {
+		return nil
+	}
+
+	return wrap(err, firstInfo(optionalInfo), func(wrapped errorImpl) Error {
+		if wrapped.stack != unsampledStack {
+			wrapped.fields = fieldsRef(contextFields(ctx))
+		}
+		return wrapped
+	})
+}
+
+func contextFields(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	var fields map[string]interface{}
+	for _, extract := range extractors {
+		for k, v := range extract(ctx) {
+			if fields == nil {
+				fields = make(map[string]interface{})
+			}
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,56 @@
+package e
+
+import (
+	"context"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func TestContextExtractors(t *testing.T) {
+	t.Cleanup(func() { extractors = nil })
+
+	RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return map[string]interface{}{"request_id": id}
+		}
+		return nil
+	})
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-123")
+
+	t.Run("NewCtx attaches fields", func(t *testing.T) {
+		err := NewCtx(ctx, CodeNotFound, "cannot find bar")
+		if got := ErrorFields(err)["request_id"]; got != "req-123" {
+			t.Errorf("\ngot:  %v\nwant: %v", got, "req-123")
+		}
+		want := "TestContextExtractors.func3: [not_found] cannot find bar"
+		if err.Error() != want {
+			t.Errorf("\ngot:  %q\nwant: %q", err, want)
+		}
+	})
+	t.Run("WrapCtx attaches fields", func(t *testing.T) {
+		err := WrapCtx(ctx, Foo(), "extra info")
+		if got := ErrorFields(err)["request_id"]; got != "req-123" {
+			t.Errorf("\ngot:  %v\nwant: %v", got, "req-123")
+		}
+		want := "TestContextExtractors.func4: (extra info): Foo: [database_error] cannot foo"
+		if err.Error() != want {
+			t.Errorf("\ngot:  %q\nwant: %q", err, want)
+		}
+	})
+	t.Run("stacktraces disabled", func(t *testing.T) {
+		SetStacktraces(false)
+		t.Cleanup(func() { SetStacktraces(true) })
+		if got := ErrorFields(NewCtx(ctx, CodeNotFound, "cannot find bar"))["request_id"]; got != "req-123" {
+			t.Errorf("\ngot:  %v\nwant: %v", got, "req-123")
+		}
+		if got := ErrorFields(WrapCtx(ctx, Foo()))["request_id"]; got != "req-123" {
+			t.Errorf("\ngot:  %v\nwant: %v", got, "req-123")
+		}
+	})
+	t.Run("WrapCtx with nil error returns nil", func(t *testing.T) {
+		if err := WrapCtx(ctx, nil); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+}
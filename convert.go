@@ -0,0 +1,96 @@
+package e
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	convertersMu sync.Mutex
+	converters   atomic.Value // []func(error) (error, bool)
+)
+
+// RegisterConverter registers fn to translate errors of third-party packages,
+// such as SDK or client errors, into errors with a code when they are wrapped.
+// Wrap and its variants consult the converters in the order they were
+// registered for errors without a code, and wrap the error returned by the
+// first converter which reports ok instead.
+//
+// The converted error should wrap err and implement ClientFacing, Retrier or
+// HasFields to carry the code, retryability and any fields. fn must not call
+// Wrap or its variants with err. RegisterConverter is safe to call
+// concurrently but is intended to be called during initialization.
+//
+// Usage:
+//
+//	func init() {
+//		e.RegisterConverter(func(err error) (error, bool) {
+//			var nsk *types.NoSuchKey
+//			if errors.As(err, &nsk) {
+//				return s3Error{code: e.CodeNotFound, err: err}, true
+//			}
+//			return nil, false
+//		})
+//	}
+func RegisterConverter(fn func(err error) (error, bool)) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+
+	fns, _ := converters.Load().([]func(error) (error, bool))
+	// copy so that concurrent readers never observe a partially updated slice
+	updated := make([]func(error) (error, bool), len(fns), len(fns)+1)
+	copy(updated, fns)
+	converters.Store(append(updated, fn))
+}
+
+// convert returns err translated by the first matching converter, or err if
+// it already has a code or no converter matches.
+func convert(err error) error {
+	fns, _ := converters.Load().([]func(error) (error, bool))
+	if len(fns) == 0 || truncated(err) || ErrorCode(err) != "" {
+		return err
+	}
+	for _, fn := range fns {
+		if converted, ok := fn(err); ok && converted != nil {
+			return converted
+		}
+	}
+	return err
+}
+
+func resetConverters() {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters.Store(([]func(error) (error, bool))(nil))
+}
+
+// Normalize turns err into an Error, so that code below a top-level handler or
+// interceptor which calls it once can assume an Error. Errors which already
+// are an Error are returned unchanged. Other errors are wrapped like Wrap,
+// which translates them with the registered converters and Classify, and
+// codes of other error types are recognized through ClientFacing and HasCode.
+// Errors whose code and kind remain unknown get KindServer. Returns nil if err
+// is nil.
+//
+// Usage:
+//
+//	func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//		if err := e.Normalize(h.serve(w, r)); err != nil {
+//			metrics.Errors.WithLabelValues(e.ErrorCode(err)).Inc()
+//			e.WriteHTTP(w, err)
+//		}
+//	}
+func Normalize(err error) Error {
+	if err == nil {
+		return nil
+	}
+	if normalized, ok := err.(Error); ok {
+		return normalized
+	}
+	return wrap(err, "", func(wrapped errorImpl) Error {
+		if ErrorCode(wrapped) == "" && ErrorKind(wrapped) == "" {
+			wrapped.kind = KindServer
+		}
+		return wrapped
+	})
+}
@@ -0,0 +1,141 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+)
+
+type sdkError struct {
+	status int
+}
+
+func (s sdkError) Error() string {
+	return "sdk: status " + strconv.Itoa(s.status)
+}
+
+// convertedSDKError carries the code of an sdkError.
+type convertedSDKError struct {
+	code      string
+	retryable bool
+	err       error
+}
+
+func (c convertedSDKError) Error() string         { return c.err.Error() }
+func (c convertedSDKError) Unwrap() error         { return c.err }
+func (c convertedSDKError) ClientCode() string    { return c.code }
+func (c convertedSDKError) ClientMessage() string { return "" }
+func (c convertedSDKError) Retryable() bool       { return c.retryable }
+
+func TestRegisterConverter(t *testing.T) {
+	var calls int
+	RegisterConverter(func(err error) (error, bool) {
+		calls++
+		var sdkErr sdkError
+		if !errors.As(err, &sdkErr) {
+			return nil, false
+		}
+		switch sdkErr.status {
+		case 404:
+			return convertedSDKError{code: CodeNotFound, err: err}, true
+		case 503:
+			return convertedSDKError{code: CodeUnavailable, retryable: true, err: err}, true
+		}
+		return nil, false
+	})
+	t.Cleanup(resetConverters)
+
+	t.Run("converts on first wrap", func(t *testing.T) {
+		err := Wrap(Wrapf(sdkError{status: 503}, "bucket: %s", "bars"))
+		if got := ErrorCode(err); got != CodeUnavailable {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeUnavailable)
+		}
+		if !IsRetryable(err) {
+			t.Errorf("expected converted error to be retryable")
+		}
+		want := "TestRegisterConverter.func2: TestRegisterConverter.func2: (bucket: bars): sdk: status 503"
+		if got := err.Error(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		var sdkErr sdkError
+		if !errors.As(err, &sdkErr) {
+			t.Errorf("expected original error to stay in the chain")
+		}
+	})
+	t.Run("every variant", func(t *testing.T) {
+		for name, err := range map[string]Error{
+			"Wrap":          Wrap(sdkError{status: 404}),
+			"Wrapw":         Wrapw(sdkError{status: 404}, "bucket", "bars"),
+			"WrapLazy":      WrapLazy(sdkError{status: 404}, func() string { return "lazy" }),
+			"ClearMessages": ClearMessages(sdkError{status: 404}),
+		} {
+			if got := ErrorCode(err); got != CodeNotFound {
+				t.Errorf("%s\ngot:  %q\nwant: %q", name, got, CodeNotFound)
+			}
+		}
+
+		var g Group
+		g.Go(func() error { return sdkError{status: 404} })
+		if got := ErrorCode(g.Wait()); got != CodeNotFound {
+			t.Errorf("Group\ngot:  %q\nwant: %q", got, CodeNotFound)
+		}
+	})
+	t.Run("skips errors with a code", func(t *testing.T) {
+		calls = 0
+		_ = Wrap(Wrap(Foo()))
+		if calls != 0 {
+			t.Errorf("expected converters not to be called but got %d calls", calls)
+		}
+	})
+	t.Run("unmatched errors are classified", func(t *testing.T) {
+		err := Wrap(os.ErrNotExist)
+		if got := ErrorCode(err); got != CodeNotFound {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeNotFound)
+		}
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	RegisterConverter(func(err error) (error, bool) {
+		var sdkErr sdkError
+		if errors.As(err, &sdkErr) && sdkErr.status == 404 {
+			return convertedSDKError{code: CodeNotFound, err: err}, true
+		}
+		return nil, false
+	})
+	t.Cleanup(resetConverters)
+
+	if got := Normalize(nil); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+	foo := Foo().(Error)
+	if got := Normalize(foo); got != foo {
+		t.Errorf("expected Error to be returned unchanged but got %v", got)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+		wantKind Kind
+		want     string
+	}{
+		{"converted", sdkError{status: 404}, CodeNotFound, "", "TestNormalize.func2: sdk: status 404"},
+		{"classified", os.ErrNotExist, CodeNotFound, "", "TestNormalize.func2: [not_found] file does not exist"},
+		{"wrapped Error", fmt.Errorf("cannot foo: %w", foo), CodeDatabase, "", "TestNormalize.func2: cannot foo: Foo: [database_error] cannot foo"},
+		{"unknown", errors.New("boom"), "", KindServer, "TestNormalize.func2: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Normalize(tt.err)
+			if got.Error() != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+			if ErrorCode(got) != tt.wantCode || ErrorKind(got) != tt.wantKind {
+				t.Errorf("\ngot:  %q %q\nwant: %q %q", ErrorCode(got), ErrorKind(got), tt.wantCode, tt.wantKind)
+			}
+		})
+	}
+}
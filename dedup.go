@@ -0,0 +1,137 @@
+package e
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultDedupWindow is used by a Deduper without a Window.
+const DefaultDedupWindow = time.Minute
+
+// Deduper suppresses repeats of errors with the same fingerprint within a time
+// window, e.g. for reconnect loops which produce thousands of identical errors
+// per minute. The zero value is ready to use and is safe for concurrent use.
+//
+// Usage:
+//
+//	var d e.Deduper
+//	for {
+//		if err := connect(); err != nil {
+//			if ok, n := d.Allow(err); ok {
+//				logger.Error("cannot connect", "error", err, "suppressed", n)
+//			}
+//			continue
+//		}
+//		...
+//	}
+type Deduper struct {
+	// Window is how long repeats of an error are suppressed after it was
+	// allowed. Defaults to DefaultDedupWindow.
+	Window time.Duration
+
+	// Fingerprint groups errors which are repeats of each other. Defaults to
+	// Fingerprint, or the text of errors without ops which would otherwise all
+	// share the same fingerprint.
+	Fingerprint func(error) string
+
+	mu   sync.Mutex
+	seen map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	err        error
+	since      time.Time
+	suppressed int
+}
+
+// Suppressed summarizes the repeats of an error suppressed by a Deduper.
+type Suppressed struct {
+	// Err is the error which was allowed at the start of the window.
+	Err         error
+	Fingerprint string
+	// Count is the number of repeats which were suppressed.
+	Count int
+	// Since is the start of the window.
+	Since time.Time
+}
+
+func (s Suppressed) String() string {
+	return "suppressed " + strconv.Itoa(s.Count) + " repeats of: " + s.Err.Error() // localizer.Ignore
+}
+
+// Allow reports whether err should be passed on: the first error with a
+// fingerprint is allowed and its repeats within the window are suppressed.
+// When an error is allowed again after its window ended, suppressed is the
+// number of repeats in the previous window. Nil errors are always allowed.
+func (d *Deduper) Allow(err error) (allowed bool, suppressed int) {
+	if err == nil {
+		return true, 0
+	}
+
+	fp := d.fingerprint(err)
+	t := now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.seen == nil {
+		d.seen = make(map[string]*dedupEntry)
+	}
+	if entry, ok := d.seen[fp]; ok {
+		if t.Sub(entry.since) < d.window() {
+			entry.suppressed++
+			return false, 0
+		}
+		suppressed = entry.suppressed
+	}
+	d.seen[fp] = &dedupEntry{err: err, since: t}
+	return true, suppressed
+}
+
+// Flush returns a summary of every window which has ended with suppressed
+// repeats, ordered by the start of the window, and forgets the errors of
+// ended windows. Call it periodically to emit count summaries and to bound the
+// memory used by errors which do not recur.
+func (d *Deduper) Flush() []Suppressed {
+	t := now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var summaries []Suppressed
+	for fp, entry := range d.seen {
+		if t.Sub(entry.since) < d.window() {
+			continue
+		}
+		if entry.suppressed > 0 {
+			summaries = append(summaries, Suppressed{
+				Err:         entry.err,
+				Fingerprint: fp,
+				Count:       entry.suppressed,
+				Since:       entry.since,
+			})
+		}
+		delete(d.seen, fp)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Since.Before(summaries[j].Since)
+	})
+	return summaries
+}
+
+func (d *Deduper) window() time.Duration {
+	if d.Window > 0 {
+		return d.Window
+	}
+	return DefaultDedupWindow
+}
+
+func (d *Deduper) fingerprint(err error) string {
+	if d.Fingerprint != nil {
+		return d.Fingerprint(err)
+	}
+	if len(Ops(err)) == 0 {
+		return err.Error()
+	}
+	return Fingerprint(err)
+}
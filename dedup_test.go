@@ -0,0 +1,54 @@
+package e
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeduper(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetTestClock(func() time.Time { return clock })
+	t.Cleanup(func() { SetTestClock(nil) })
+
+	d := Deduper{Window: time.Minute}
+	if ok, _ := d.Allow(Bar()); !ok {
+		t.Errorf("expected first error to be allowed")
+	}
+	for i := 0; i < 3; i++ {
+		if ok, _ := d.Allow(Bar()); ok {
+			t.Errorf("expected repeat to be suppressed")
+		}
+	}
+	if ok, _ := d.Allow(Fizz()); !ok {
+		t.Errorf("expected error with another fingerprint to be allowed")
+	}
+	if ok, _ := d.Allow(errors.New("foreign a")); !ok {
+		t.Errorf("expected foreign error to be allowed")
+	}
+	if ok, _ := d.Allow(errors.New("foreign b")); !ok {
+		t.Errorf("expected foreign error with other text to be allowed")
+	}
+
+	clock = clock.Add(time.Minute)
+	if ok, n := d.Allow(Bar()); !ok || n != 3 {
+		t.Errorf("expected error to be allowed after window with 3 suppressed but got %v, %d", ok, n)
+	}
+	d.Allow(Bar())
+
+	if got := d.Flush(); len(got) != 0 {
+		t.Errorf("expected no ended windows but got %v", got)
+	}
+	clock = clock.Add(time.Minute)
+	got := d.Flush()
+	if len(got) != 1 || got[0].Count != 1 || got[0].Fingerprint != Fingerprint(Bar()) {
+		t.Fatalf("unexpected summaries %v", got)
+	}
+	want := "suppressed 1 repeats of: Bar: Foo: [database_error] cannot foo"
+	if got := got[0].String(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if ok, n := d.Allow(Bar()); !ok || n != 0 {
+		t.Errorf("expected flushed error to be allowed without suppressed count but got %v, %d", ok, n)
+	}
+}
@@ -0,0 +1,121 @@
+package e
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Attach returns a copy of err carrying detail, a strongly-typed payload such
+// as rate limit information which can be retrieved by type at handling sites
+// with Detail. Returns nil if err is nil.
+//
+// Details are not printed with Error() and are not preserved by Encode; use
+// SetDetailJSON for details which should be.
+//
+// Usage:
+//
+//	type RateLimitInfo struct {
+//		RetryAfter time.Duration
+//	}
+//
+//	return e.Attach(e.NewError(CodeRateLimited, "too many requests"), RateLimitInfo{RetryAfter: time.Minute})
+//
+//	// handling site
+//	if info, ok := e.Detail[RateLimitInfo](err); ok {
+//		w.Header().Set("Retry-After", strconv.Itoa(int(info.RetryAfter.Seconds())))
+//	}
+func Attach[T any](err Error, detail T) Error {
+	switch x := err.(type) {
+	case nil:
+		return nil
+	case errorImpl:
+		return x.withDetail(detail)
+	case ValidationError:
+		x.errorImpl = x.errorImpl.withDetail(detail)
+		return x
+	default:
+		return errorImpl{err: err, stack: innerStack(err)}.withDetail(detail)
+	}
+}
+
+// Detail returns the first detail of type T attached to an error in the chain
+// with Attach. Details attached later take precedence over earlier ones.
+func Detail[T any](err error) (T, bool) {
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok && impl.details != nil {
+			details := *impl.details
+			for i := len(details) - 1; i >= 0; i-- {
+				if detail, ok := details[i].(T); ok {
+					return detail, true
+				}
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func (e errorImpl) withDetail(detail interface{}) errorImpl {
+	// copy so that errors sharing the same slice are not affected
+	var details []interface{}
+	if e.details != nil {
+		details = append(details, *e.details...)
+	}
+	details = append(details, detail)
+	e.details = &details
+	return e
+}
+
+// jsonDetail is a detail set with SetDetailJSON.
+type jsonDetail struct {
+	name string
+	raw  json.RawMessage
+}
+
+func (e errorImpl) SetDetailJSON(name string, detail json.RawMessage) Error {
+	if !json.Valid(detail) {
+		// keep invalid JSON as a string so that Encode still succeeds
+		detail, _ = json.Marshal(string(detail))
+	} else {
+		detail = bytes.Clone(detail)
+	}
+	return e.withDetail(jsonDetail{name: name, raw: detail})
+}
+
+// DetailJSON returns the detail set with SetDetailJSON under name by the
+// outermost error in the chain of err which set one. Otherwise returns false.
+//
+// Usage:
+//
+//	if raw, ok := e.DetailJSON(err, "validation"); ok {
+//		var result ValidationResult
+//		_ = json.Unmarshal(raw, &result)
+//	}
+func DetailJSON(err error, name string) (json.RawMessage, bool) {
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok {
+			if raw, ok := impl.detailsJSON()[name]; ok {
+				return raw, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// detailsJSON returns the details set with SetDetailJSON on e by name, or nil
+// if there are none. Details set later take precedence over earlier ones.
+func (e errorImpl) detailsJSON() map[string]json.RawMessage {
+	if e.details == nil {
+		return nil
+	}
+	var details map[string]json.RawMessage
+	for _, detail := range *e.details {
+		if d, ok := detail.(jsonDetail); ok {
+			if details == nil {
+				details = make(map[string]json.RawMessage)
+			}
+			details[d.name] = d.raw
+		}
+	}
+	return details
+}
@@ -0,0 +1,95 @@
+package e
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type rateLimitInfo struct {
+	RetryAfter time.Duration
+}
+
+type quotaInfo struct {
+	Limit int
+}
+
+func TestDetail(t *testing.T) {
+	base := Attach(NewError(CodeInternal, "too many requests"), rateLimitInfo{RetryAfter: time.Second})
+	err := Attach(Wrap(base), quotaInfo{Limit: 10})
+	err = Attach(err, rateLimitInfo{RetryAfter: time.Minute})
+
+	if got, ok := Detail[rateLimitInfo](err); !ok || got.RetryAfter != time.Minute {
+		t.Errorf("expected latest rate limit detail but got %+v, %v", got, ok)
+	}
+	if got, ok := Detail[quotaInfo](err); !ok || got.Limit != 10 {
+		t.Errorf("expected quota detail but got %+v, %v", got, ok)
+	}
+	if got, ok := Detail[rateLimitInfo](base); !ok || got.RetryAfter != time.Second {
+		t.Errorf("expected Attach not to modify the receiver but got %+v, %v", got, ok)
+	}
+	if _, ok := Detail[string](err); ok {
+		t.Errorf("expected no detail of type string")
+	}
+	if !errors.Is(err, base) {
+		t.Errorf("expected errors.Is to match through attached details")
+	}
+}
+
+func TestDetailJSON(t *testing.T) {
+	raw := json.RawMessage(`{"field":"name","reason":"required"}`)
+	err := Wrap(Foo()).SetDetailJSON("validation", raw).SetDetailJSON("response", json.RawMessage("<html>"))
+	raw[0] = '['
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"set", err},
+		{"wrapped", Wrap(err)},
+		{"validation error", NewValidation().AddField("name", "required").SetDetailJSON("validation", json.RawMessage(`{"field":"name","reason":"required"}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetailJSON(tt.err, "validation")
+			if want := `{"field":"name","reason":"required"}`; !ok || string(got) != want {
+				t.Errorf("\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+
+	if got, _ := DetailJSON(err, "response"); string(got) != `"\u003chtml\u003e"` {
+		t.Errorf("expected invalid JSON to be kept as a string but got %s", got)
+	}
+	if _, ok := DetailJSON(err, "other"); ok {
+		t.Errorf("expected no detail named other")
+	}
+
+	encoded := Encode(Wrap(err))
+	if !bytes.Contains(encoded, []byte(`"details":{"response":"\u003chtml\u003e","validation":{"field":"name","reason":"required"}}`)) {
+		t.Errorf("expected details to be embedded verbatim but got %s", encoded)
+	}
+	decoded, decodeErr := Decode(encoded)
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if got, _ := DetailJSON(decoded, "validation"); string(got) != `{"field":"name","reason":"required"}` {
+		t.Errorf("expected decoded detail but got %s", got)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	if got := Attach[int](nil, 1); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+
+	v := Attach(NewValidation().AddField("email", "must be valid"), quotaInfo{Limit: 1})
+	if _, ok := v.(ValidationError); !ok {
+		t.Errorf("expected Attach to keep ValidationError type but got %T", v)
+	}
+	if _, ok := Detail[quotaInfo](v); !ok {
+		t.Errorf("expected detail on ValidationError")
+	}
+}
@@ -0,0 +1,96 @@
+package e
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	docURLTemplate atomic.Pointer[string]
+
+	docURLsMu sync.Mutex
+	docURLs   atomic.Pointer[map[string]string]
+)
+
+// RegisterDocURL links code to documentation such as a runbook, taking
+// precedence over the DocURL of the CodeInfo registered for it. Unlike
+// RegisterCode it does not register code, so that the policy registered for
+// its namespace still applies. An empty docURL removes the link.
+//
+// Usage:
+//
+//	func init() {
+//		e.RegisterDocURL(CodeQuotaExceeded, "https://docs.example.com/errors/quota")
+//	}
+func RegisterDocURL(code, docURL string) {
+	docURLsMu.Lock()
+	defer docURLsMu.Unlock()
+
+	updated := make(map[string]string)
+	if m := docURLs.Load(); m != nil {
+		for k, v := range *m {
+			updated[k] = v
+		}
+	}
+	if docURL != "" {
+		updated[code] = docURL
+	} else {
+		delete(updated, code)
+	}
+	docURLs.Store(&updated)
+}
+
+// SetDocURLTemplate sets the documentation URL of codes which have no DocURL
+// registered. Every "{code}" in template is replaced with the path-escaped
+// code. An empty template removes the current one.
+//
+// Usage:
+//
+//	e.SetDocURLTemplate("https://docs.example.com/errors/{code}")
+func SetDocURLTemplate(template string) {
+	if template == "" {
+		docURLTemplate.Store(nil)
+		return
+	}
+	docURLTemplate.Store(&template)
+}
+
+// ErrorDocURL returns the DocURL of a CodeInfo attached to err with Attach,
+// the one registered for the first code of err or for its closest registered
+// parent, or the URL built from the template set with SetDocURLTemplate.
+// Returns an empty string if err has no code.
+func ErrorDocURL(err error) string {
+	if override, ok := Detail[CodeInfo](err); ok && override.DocURL != "" {
+		return override.DocURL
+	}
+	code := ErrorCode(err)
+	if code == "" {
+		return ""
+	}
+	if docURL, ok := lookupNearestDocURL(code); ok {
+		return docURL
+	}
+	if template := docURLTemplate.Load(); template != nil {
+		return strings.ReplaceAll(*template, "{code}", url.PathEscape(code))
+	}
+	return ""
+}
+
+// lookupNearestDocURL returns the DocURL registered for code or for its
+// closest parent which has one.
+func lookupNearestDocURL(code string) (string, bool) {
+	m := docURLs.Load()
+	for ; code != ""; code = parentCode(code) {
+		if m != nil {
+			if docURL, ok := (*m)[code]; ok {
+				return docURL, true
+			}
+		}
+		if info, ok := LookupCode(code); ok && info.DocURL != "" {
+			return info.DocURL, true
+		}
+	}
+	return "", false
+}
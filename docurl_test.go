@@ -0,0 +1,74 @@
+package e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorDocURL(t *testing.T) {
+	RegisterCode("quota_exceeded", CodeInfo{Description: "The tenant has used up its request quota."})
+	RegisterCode("storage", CodeInfo{HTTPStatus: http.StatusServiceUnavailable})
+	RegisterDocURL("quota_exceeded", "https://docs.example.com/runbooks/quota")
+	RegisterDocURL("storage", "https://docs.example.com/runbooks/storage")
+	RegisterDocURL("storage.postgres", "https://docs.example.com/runbooks/postgres")
+	t.Cleanup(func() {
+		delete(codeInfos, "quota_exceeded")
+		delete(codeInfos, "storage")
+		docURLs.Store(nil)
+	})
+
+	if info, _ := LookupCode("quota_exceeded"); info.Description == "" {
+		t.Errorf("expected RegisterDocURL to keep the description")
+	}
+	if _, ok := LookupCode("storage.postgres"); ok {
+		t.Errorf("expected RegisterDocURL to not register the code")
+	}
+	if got := HTTPStatus(NewError("storage.postgres.timeout", "too slow")); got != http.StatusServiceUnavailable {
+		t.Errorf("expected the policy of the namespace to apply but got %d", got)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"registered", Wrap(NewError("quota_exceeded", "tenant 12")), "https://docs.example.com/runbooks/quota"},
+		{"parent code", NewError("storage.postgres.timeout", "too slow"), "https://docs.example.com/runbooks/postgres"},
+		{"namespace", NewError("storage.redis", "too slow"), "https://docs.example.com/runbooks/storage"},
+		{"attached", Attach(Foo().(Error), CodeInfo{DocURL: "https://docs.example.com/runbooks/foo"}), "https://docs.example.com/runbooks/foo"},
+		{"unregistered", Foo(), ""},
+		{"no code", Wrap(errSentinel).SetCode(""), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorDocURL(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("template", func(t *testing.T) {
+		SetDocURLTemplate("https://docs.example.com/errors/{code}")
+		t.Cleanup(func() { SetDocURLTemplate("") })
+
+		if got, want := ErrorDocURL(Foo()), "https://docs.example.com/errors/database_error"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got, want := ErrorDocURL(NewError("quota_exceeded", "tenant 12")), "https://docs.example.com/runbooks/quota"; got != want {
+			t.Errorf("expected registered url to take precedence\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("WriteHTTP", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, NewError("quota_exceeded", "tenant 12"))
+		var body httpBody
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.DocURL != "https://docs.example.com/runbooks/quota" {
+			t.Errorf("\ngot:  %q\nwant: %q", body.DocURL, "https://docs.example.com/runbooks/quota")
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package e
+
+import (
+	"sort"
+	"sync"
+)
+
+// Edge is a pair of ops where the error returned by Inner was wrapped by
+// Outer.
+type Edge struct {
+	Outer string
+	Inner string
+}
+
+// EdgeCollector counts the edges which errors cross as they are propagated,
+// e.g. to draw a graph of which layers wrap the errors of which other layers
+// for architecture reviews. Collecting is opt-in by registering Observe with
+// AddHook. The zero value is ready to use and is safe for concurrent use.
+//
+// Usage:
+//
+//	var edges e.EdgeCollector
+//	e.AddHook(edges.Observe)
+//	...
+//	for outer, inners := range edges.AdjacencyList() {
+//		fmt.Println(outer, "->", strings.Join(inners, ", "))
+//	}
+type EdgeCollector struct {
+	mu    sync.Mutex
+	edges map[Edge]int
+}
+
+// Observe records the edge between the outermost op of err and the next op in
+// its chain, if any. Wraps within the same function are not recorded. err is
+// returned unchanged so that Observe can be registered with AddHook.
+func (c *EdgeCollector) Observe(err Error) Error {
+	var edge Edge
+	for err := range chain(err) {
+		impl, ok := asImpl(err)
+		if !ok || impl.op == "" {
+			continue
+		}
+		if edge.Outer == "" {
+			edge.Outer = impl.op
+			continue
+		}
+		edge.Inner = impl.op
+		break
+	}
+	if edge.Inner == "" || edge.Inner == edge.Outer {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.edges == nil {
+		c.edges = make(map[Edge]int)
+	}
+	c.edges[edge]++
+	return err
+}
+
+// Edges returns the number of times each edge was observed.
+func (c *EdgeCollector) Edges() map[Edge]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	edges := make(map[Edge]int, len(c.edges))
+	for edge, n := range c.edges {
+		edges[edge] = n
+	}
+	return edges
+}
+
+// AdjacencyList returns the sorted inner ops of every observed outer op.
+func (c *EdgeCollector) AdjacencyList() map[string][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	list := make(map[string][]string)
+	for edge := range c.edges {
+		list[edge.Outer] = append(list[edge.Outer], edge.Inner)
+	}
+	for _, inners := range list {
+		sort.Strings(inners)
+	}
+	return list
+}
+
+// Reset forgets all observed edges.
+func (c *EdgeCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.edges = nil
+}
@@ -0,0 +1,41 @@
+package e
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestEdgeCollector(t *testing.T) {
+	var c EdgeCollector
+	AddHook(c.Observe)
+	t.Cleanup(hooks.reset)
+
+	_ = Bar()
+	_ = Bar()
+	_ = Fizz()
+	_ = FizzBuzz()
+	_ = Wrap(Wrap(fmt.Errorf("EOF")))
+
+	wantEdges := map[Edge]int{
+		{Outer: "Bar", Inner: "Foo"}:      2,
+		{Outer: "Fizz", Inner: "Foo"}:     1,
+		{Outer: "FizzBuzz", Inner: "Foo"}: 1,
+	}
+	if got := c.Edges(); !reflect.DeepEqual(got, wantEdges) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, wantEdges)
+	}
+	wantList := map[string][]string{
+		"Bar":      {"Foo"},
+		"Fizz":     {"Foo"},
+		"FizzBuzz": {"Foo"},
+	}
+	if got := c.AdjacencyList(); !reflect.DeepEqual(got, wantList) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, wantList)
+	}
+
+	c.Reset()
+	if got := c.Edges(); len(got) != 0 {
+		t.Errorf("expected no edges after Reset but got %v", got)
+	}
+}
@@ -0,0 +1,250 @@
+package e
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// wireError is the stable JSON representation used by Encode and Decode.
+type wireError struct {
+	// Chain is ordered from outermost to innermost error.
+	Chain      []wireNode `json:"chain"`
+	Stacktrace string     `json:"stacktrace,omitempty"`
+}
+
+type wireNode struct {
+	Op            string        `json:"op,omitempty"`
+	Info          string        `json:"info,omitempty"`
+	Code          string        `json:"code,omitempty"`
+	Kind          Kind          `json:"kind,omitempty"`
+	Message       string        `json:"message,omitempty"`
+	Hint          string        `json:"hint,omitempty"`
+	HidesMessages bool          `json:"hidesMessages,omitempty"`
+	ID            string        `json:"id,omitempty"`
+	Source        string        `json:"source,omitempty"`
+	Retryable     bool          `json:"retryable,omitempty"`
+	NotRetryable  bool          `json:"notRetryable,omitempty"`
+	RetryAfter    time.Duration `json:"retryAfter,omitempty"`
+	Timeout       bool          `json:"timeout,omitempty"`
+	Temporary     bool          `json:"temporary,omitempty"`
+
+	Upstream *UpstreamInfo `json:"upstream,omitempty"`
+
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Caller    string    `json:"caller,omitempty"`
+
+	Fields  map[string]interface{}     `json:"fields,omitempty"`
+	Details map[string]json.RawMessage `json:"details,omitempty"`
+
+	// Foreign is set for errors not created by package e. Text holds the
+	// error string excluding the string of the error it wraps.
+	Foreign bool   `json:"foreign,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+// Encode serializes the full error chain, including ops, codes, messages,
+// hints, ids, sources, upstreams, timestamps, callers, fields, details set with
+// SetDetailJSON and the stacktrace, so it can be shipped to another process
+// and rehydrated with Decode. Returns nil if err is nil.
+//
+// Errors not created by package e are preserved as text along with any code,
+// message, retryability and fields they expose through ClientFacing, Retrier
+// and HasFields.
+//
+// Field values are encoded as JSON and decoded into their generic JSON
+// representation (e.g. numbers become float64).
+func Encode(err error) []byte {
+	if err == nil {
+		return nil
+	}
+
+	w := wireError{Stacktrace: ErrorStacktrace(err)}
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok {
+			w.Chain = append(w.Chain, wireNode{
+				Op:            impl.op,
+				Info:          redact(impl.info),
+				Code:          impl.code,
+				Kind:          impl.kind,
+				Message:       redact(impl.message),
+				Hint:          impl.hint,
+				HidesMessages: impl.hidesMessages,
+				ID:            impl.id,
+				Source:        impl.source,
+				Retryable:     impl.retryable,
+				NotRetryable:  impl.retryableSet && !impl.retryable,
+				RetryAfter:    impl.retryAfter,
+				Timeout:       impl.timeout,
+				Temporary:     impl.temporary,
+				Upstream:      impl.upstream,
+				Timestamp:     impl.created,
+				Caller:        impl.caller.String(),
+				Fields:        impl.Fields(),
+				Details:       impl.detailsJSON(),
+			})
+			continue
+		}
+
+		text, separated := ownText(err, errors.Unwrap(err))
+		node := wireNode{Foreign: true, Text: redact(text)}
+		node.Code = codeOf(err)
+		node.Message = messageOf(err)
+		if r, ok := err.(Retrier); ok {
+			node.Retryable = r.Retryable()
+		}
+		if hf, ok := err.(HasFields); ok {
+			node.Fields = hf.Fields()
+		}
+		w.Chain = append(w.Chain, node)
+		// Only continue down the chain if the inner text can be separated.
+		if !separated {
+			break
+		}
+	}
+
+	b, _ := json.Marshal(w)
+	return b
+}
+
+// ownText returns the part of err.Error() which does not belong to the error it
+// wraps. separated is false if the text of inner cannot be split off (or inner
+// is nil), in which case the full text of err is returned.
+func ownText(err, inner error) (text string, separated bool) {
+	text = err.Error()
+	if inner == nil || !strings.HasSuffix(text, inner.Error()) {
+		return text, false
+	}
+	return strings.TrimSuffix(text, inner.Error()), true
+}
+
+// Decode rehydrates an error serialized with Encode.
+func Decode(data []byte) (Error, error) {
+	var w wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, Wrap(err).SetCode(CodeInvalid)
+	}
+	if len(w.Chain) == 0 {
+		return nil, NewError(CodeInvalid, "encoded error has empty chain")
+	}
+
+	var err error
+	stack := stackText(w.Stacktrace)
+	for i := len(w.Chain) - 1; i >= 0; i-- {
+		node := w.Chain[i]
+		if node.Foreign {
+			err = &frozenError{
+				text:      node.Text,
+				code:      node.Code,
+				message:   node.Message,
+				retryable: node.Retryable,
+				fields:    node.Fields,
+				err:       err,
+			}
+			continue
+		}
+		if err == nil {
+			return nil, NewError(CodeInvalid, "encoded error has no root cause")
+		}
+		impl := errorImpl{
+			op:            node.Op,
+			info:          node.Info,
+			code:          node.Code,
+			kind:          node.Kind,
+			message:       node.Message,
+			hint:          node.Hint,
+			hidesMessages: node.HidesMessages,
+			id:            node.ID,
+			source:        node.Source,
+			retryable:     node.Retryable,
+			retryableSet:  node.Retryable || node.NotRetryable,
+			retryAfter:    node.RetryAfter,
+			timeout:       node.Timeout,
+			temporary:     node.Temporary,
+			upstream:      node.Upstream,
+			created:       node.Timestamp,
+			caller:        frame{text: node.Caller},
+			fields:        fieldsRef(node.Fields),
+			err:           err,
+			stack:         stack,
+		}
+		if len(node.Details) > 0 {
+			details := make([]interface{}, 0, len(node.Details))
+			for name, raw := range node.Details {
+				details = append(details, jsonDetail{name: name, raw: raw})
+			}
+			impl.details = &details
+		}
+		err = impl
+	}
+
+	if impl, ok := err.(errorImpl); ok {
+		return impl, nil
+	}
+	return errorImpl{err: err, stack: stack}, nil
+}
+
+func init() {
+	// Allow Errors to be sent as values of error and Error in gob streams,
+	// e.g. in job results and net/rpc replies.
+	gob.Register(errorImpl{})
+	gob.Register(ValidationError{})
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, which is also used by
+// encoding/gob, with the format of Encode.
+func (e errorImpl) MarshalBinary() ([]byte, error) {
+	return Encode(e), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, which is also used by
+// encoding/gob, with the format of Decode.
+func (e *errorImpl) UnmarshalBinary(data []byte) error {
+	decoded, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	*e, _ = asImpl(decoded)
+	return nil
+}
+
+// frozenError stands in for an error which was not created by package e
+// after it has been decoded. It is used by pointer so that errors wrapping it
+// stay comparable for errors.Is.
+type frozenError struct {
+	text      string
+	code      string
+	message   string
+	retryable bool
+	fields    map[string]interface{}
+	err       error
+}
+
+func (f frozenError) Error() string {
+	if f.err == nil {
+		return f.text
+	}
+	return f.text + f.err.Error()
+}
+
+func (f frozenError) Unwrap() error {
+	return f.err
+}
+
+func (f frozenError) ClientCode() string {
+	return f.code
+}
+
+func (f frozenError) ClientMessage() string {
+	return f.message
+}
+
+func (f frozenError) Retryable() bool {
+	return f.retryable
+}
+
+func (f frozenError) Fields() map[string]interface{} {
+	return f.fields
+}
@@ -0,0 +1,127 @@
+package e
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "new error",
+			err:  Foo(),
+		},
+		{
+			name: "wrapped error with optional info",
+			err:  Fizz(),
+		},
+		{
+			name: "multiple codes and non-pkg wrapping",
+			err:  FizzBuzzWhiz(),
+		},
+		{
+			name: "non-pkg root error",
+			err:  Wrap(errors.New("basic error")).SetMessage("oh no").SetRetryable(true),
+		},
+		{
+			name: "fields",
+			err:  Wrap(Foo()).SetField("id", "2hs8qh9").SetField("attempt", 3.0),
+		},
+		{
+			name: "non-pkg outer error",
+			err:  fmt.Errorf("outer: %w", Foo()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(Encode(tt.err))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Error() != tt.err.Error() {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.err)
+			}
+			if ErrorCode(got) != ErrorCode(tt.err) {
+				t.Errorf("\ngot code:  %q\nwant code: %q", ErrorCode(got), ErrorCode(tt.err))
+			}
+			if ErrorMessage(got) != ErrorMessage(tt.err) {
+				t.Errorf("\ngot message:  %q\nwant message: %q", ErrorMessage(got), ErrorMessage(tt.err))
+			}
+			if IsRetryable(got) != IsRetryable(tt.err) {
+				t.Errorf("\ngot retryable:  %v\nwant retryable: %v", IsRetryable(got), IsRetryable(tt.err))
+			}
+			if !reflect.DeepEqual(Ops(got), Ops(tt.err)) {
+				t.Errorf("\ngot ops:  %q\nwant ops: %q", Ops(got), Ops(tt.err))
+			}
+			if !reflect.DeepEqual(ErrorFields(got), ErrorFields(tt.err)) {
+				t.Errorf("\ngot fields:  %v\nwant fields: %v", ErrorFields(got), ErrorFields(tt.err))
+			}
+			if !errors.Is(got, got) {
+				t.Errorf("expected decoded error to work with errors.Is")
+			}
+			if ErrorStacktrace(got) != ErrorStacktrace(tt.err) {
+				t.Errorf("expected stacktrace to be preserved")
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, data := range []string{"", "not json", `{"chain":[]}`, `{"chain":[{"op":"Foo"}]}`} {
+		if _, err := Decode([]byte(data)); ErrorCode(err) != CodeInvalid {
+			t.Errorf("expected %q to fail with %q but got %v", data, CodeInvalid, err)
+		}
+	}
+}
+
+func TestGob(t *testing.T) {
+	type result struct {
+		ID  int
+		Err error
+	}
+	err := Wrap(Fizz()).SetMessage("Please try again").SetField("bar_id", "2hs8qh9")
+
+	var buf bytes.Buffer
+	if encodeErr := gob.NewEncoder(&buf).Encode(result{ID: 1, Err: err}); encodeErr != nil {
+		t.Fatal(encodeErr)
+	}
+	var got result
+	if decodeErr := gob.NewDecoder(&buf).Decode(&got); decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+
+	if got.Err.Error() != err.Error() {
+		t.Errorf("\ngot:  %q\nwant: %q", got.Err.Error(), err.Error())
+	}
+	if !reflect.DeepEqual(Ops(got.Err), Ops(err)) {
+		t.Errorf("\ngot:  %q\nwant: %q", Ops(got.Err), Ops(err))
+	}
+	if got, want := ErrorMessage(got.Err), "Please try again"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := ErrorFields(got.Err), ErrorFields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+
+	t.Run("validation error", func(t *testing.T) {
+		var buf bytes.Buffer
+		verr := validateUser("", 1)
+		if encodeErr := gob.NewEncoder(&buf).Encode(&verr); encodeErr != nil {
+			t.Fatal(encodeErr)
+		}
+		var got error
+		if decodeErr := gob.NewDecoder(&buf).Decode(&got); decodeErr != nil {
+			t.Fatal(decodeErr)
+		}
+		if got.Error() != verr.Error() || ErrorCode(got) != CodeValidation {
+			t.Errorf("\ngot:  %q\nwant: %q", got, verr)
+		}
+	})
+}
@@ -3,20 +3,36 @@
 package e
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
-	"runtime/debug"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Error represents a standard application error.
 // Implements ClientFacing and HasStacktrace so it can be introspected
 // with functions like ErrorCode, ErrorMessage, and ErrorStacktrace.
+//
+// Errors are immutable: setters such as SetCode return a modified copy and
+// never change the receiver. An Error can therefore be decorated by one
+// goroutine while another goroutine formats or inspects it. Always use the
+// returned Error:
+//
+//	err = err.SetCode(CodeInternal) // err.SetCode(CodeInternal) alone has no effect
 type Error interface {
 	error
 	ClientFacing
 	HasStacktrace
+	Retrier
+	HasFields
+	HasID
+	HasKind
+	HasTimestamp
+	HasRetryAfter
+	HasHint
 
 	Unwrap() error
 
@@ -31,6 +47,85 @@ type Error interface {
 	//
 	// Will panic when used with a nil Error receiver.
 	SetMessage(message string) Error
+
+	// SetHint adds actionable remediation for the user, such as "re-run with
+	// --force", to a non-nil Error. Unlike the message it describes what to do
+	// about the error. Hint will not be printed with Error() and should be
+	// retrieved with ErrorHint().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetHint(hint string) Error
+
+	// SetRetryable marks whether the operation which caused a non-nil Error
+	// can be safely retried. Use IsRetryable() to inspect the error chain.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetRetryable(retryable bool) Error
+
+	// SetRetryAfter hints how long clients should wait before retrying, e.g.
+	// when rate limited. Also marks the Error as retryable. Use
+	// ErrorRetryAfter() to inspect the error chain.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetRetryAfter(d time.Duration) Error
+
+	// SetKind assigns a broad category such as KindClient to a non-nil Error,
+	// alongside its code. Use ErrorKind() to inspect the error chain.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetKind(kind Kind) Error
+
+	// SetField adds a structured key-value pair to a non-nil Error, such as an
+	// id or a request parameter. Fields will not be printed with Error() and
+	// should be retrieved with ErrorFields().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetField(key string, value interface{}) Error
+
+	// SetTimeout marks whether a non-nil Error was caused by a timeout.
+	// Wrap infers it from wrapped errors which implement net.Error.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetTimeout(timeout bool) Error
+
+	// SetTemporary marks whether a non-nil Error is temporary.
+	// Wrap infers it from wrapped errors which implement net.Error.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetTemporary(temporary bool) Error
+
+	// SetID assigns a unique id to a non-nil Error, which can be given to
+	// end-users to correlate a reported error with logs. Ids are generated
+	// automatically once SetIDGenerator has been called.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetID(id string) Error
+
+	// SetUpstream records which external dependency caused a non-nil Error:
+	// the system, such as "stripe", the endpoint which was called and the
+	// status it returned, or 0 if it did not respond. Use Upstream() to
+	// inspect the error chain.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetUpstream(system, endpoint string, status int) Error
+
+	// SetDetailJSON attaches detail under name to a non-nil Error, a
+	// schemaless payload such as the body of an upstream response which
+	// is embedded verbatim in the details of Encode and restored by Decode.
+	// Invalid JSON is kept as a JSON string. Use DetailJSON() to inspect the
+	// error chain.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetDetailJSON(name string, detail json.RawMessage) Error
+
+	// With wraps a non-nil Error like Wrap, adding the name of the calling
+	// function and the first optionalInfo, if any. It is a method-style
+	// alternative to Wrap for fluent re-wrapping, e.g. in helper types which
+	// already hold an Error. The code and message are preserved since they are
+	// inherited from the wrapped Error.
+	//
+	// Will panic when used with a nil Error receiver.
+	With(optionalInfo ...string) Error
 }
 
 // NewError constructs a new Error. code should be a short, single string
@@ -46,12 +141,7 @@ type Error interface {
 //		}
 //
 func NewError(code, cause string) Error {
-	return errorImpl{
-		op:         getCallingFunc(2),
-		code:       code,
-		err:        errors.New(cause),
-		stacktrace: string(debug.Stack()),
-	}
+	return runNewHooks(newImpl(getCallingFunc(2), code, errors.New(cause)))
 }
 
 // NewErrorf constructs a new Error with formatted string. code should be a short,
@@ -68,18 +158,37 @@ func NewError(code, cause string) Error {
 //		}
 //
 func NewErrorf(code, fmtCause string, args ...interface{}) Error {
-	return errorImpl{
-		op:         getCallingFunc(2),
-		code:       code,
-		err:        fmt.Errorf(fmtCause, args...),
-		stacktrace: string(debug.Stack()),
+	return runNewHooks(newImpl(getCallingFunc(2), code, fmt.Errorf(fmtCause, args...)))
+}
+
+// newImpl constructs the errorImpl at the root of a new error stack.
+func newImpl(op, code string, cause error) errorImpl {
+	created := errorImpl{
+		op:     op,
+		code:   code,
+		id:     generateID(),
+		source: currentSource(),
+		err:    cause,
+		stack:  unsampledStack,
+	}
+	if sampled(code) {
+		created.created = timestamp()
+		created.stack = callers(2)
 	}
+	created.caller = recordCaller(2)
+	return created
 }
 
 // Wrap adds the name of the calling function to the wrapped error.
 // OptionalInfo can be passed to insert more context at the wrap site.
 // Only the first OptionalInfo string will be used.
 //
+// If err does not carry a code, a canonical code is assigned with Classify.
+// Otherwise its code is escalated by the rules set with SetPromotionRule.
+//
+// Returns nil if err is nil, so the result of a call can be wrapped directly.
+// Setters must not be chained onto the result unless err is known to be non-nil.
+//
 // Basic usage:
 // 		err := Foo()
 //		if err != nil {
@@ -97,26 +206,11 @@ func Wrap(err error, optionalInfo ...string) Error {
 		return nil
 	}
 
-	innerErr := err
-	if len(optionalInfo) > 0 {
-		innerErr = fmt.Errorf("(%v): %w", optionalInfo[0], err) // localizer.Ignore
-	}
-
-	wrapped := errorImpl{
-		op:         getCallingFunc(2),
-		err:        innerErr,
-		stacktrace: ErrorStacktrace(err),
-	}
-
-	if wrapped.stacktrace == "" {
-		wrapped.stacktrace = string(debug.Stack())
-	}
-
-	return wrapped
+	return wrap(err, firstInfo(optionalInfo), nil)
 }
 
 // Wrapf adds the name of the calling function and a formatted message
-// to the wrapped error.
+// to the wrapped error. Returns nil if err is nil.
 //
 // Basic usage:
 // 		err := Foo(bar)
@@ -129,53 +223,364 @@ func Wrapf(err error, fmtInfo string, args ...interface{}) Error {
 		return nil
 	}
 
+	return wrap(err, fmt.Sprintf(fmtInfo, args...), nil)
+}
+
+// badKey is used by Wrapw as the key of values which are missing a string key,
+// like in log/slog.
+const badKey = "!BADKEY"
+
+// Wrapw adds the name of the calling function and alternating key-value pairs
+// to the wrapped error. The pairs are stored as fields and printed as info,
+// e.g. "(attempt=3 host=db1)". A value without a string key gets the key
+// "!BADKEY". Returns nil if err is nil.
+//
+// Basic usage:
+// 		err := Dial(host)
+//		if err != nil {
+// 			return e.Wrapw(err, "attempt", attempt, "host", host)
+// 		}
+//
+func Wrapw(err error, keysAndValues ...interface{}) Error {
+	if err == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, (len(keysAndValues)+1)/2)
+	var sb strings.Builder
+	for len(keysAndValues) > 0 {
+		key, ok := keysAndValues[0].(string)
+		var value interface{}
+		switch {
+		case !ok:
+			key, value = badKey, keysAndValues[0]
+			keysAndValues = keysAndValues[1:]
+		case len(keysAndValues) == 1:
+			key, value = badKey, key
+			keysAndValues = nil
+		default:
+			value = keysAndValues[1]
+			keysAndValues = keysAndValues[2:]
+		}
+		fields[key] = value
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(key)
+		sb.WriteString("=")
+		sb.WriteString(quoteValue(fmt.Sprint(value)))
+	}
+	return wrap(err, sb.String(), func(wrapped errorImpl) Error {
+		wrapped.fields = fieldsRef(fields)
+		return wrapped
+	})
+}
+
+// quoteValue quotes a value printed by Wrapw if it would be ambiguous
+// otherwise.
+func quoteValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"()") || strconv.Quote(s) != `"`+s+`"` {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// ClearMessages wraps err so that the messages of err and every error it wraps
+// are hidden from ErrorMessage and Messages, e.g. before returning an error
+// whose inner messages may contain sensitive details to a client. Unlike
+// SetMessage(""), which only clears the message of the receiver and lets inner
+// messages show through, it covers the entire chain. A message set on the
+// returned Error is still shown. Returns nil if err is nil.
+//
+// Usage:
+//
+//	if err != nil {
+//		return e.ClearMessages(err).SetMessage("Payment failed")
+//	}
+func ClearMessages(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	return wrap(err, "", func(wrapped errorImpl) Error {
+		wrapped.hidesMessages = true
+		return wrapped
+	})
+}
+
+// hidesMessages reports whether err hides the messages of the errors it wraps.
+func hidesMessages(err error) bool {
+	impl, ok := asImpl(err)
+	return ok && impl.hidesMessages
+}
+
+// wrap implements Wrap and its variants: it wraps the non-nil err with the
+// name of the function calling its caller and info, lets decorate, if not
+// nil, set further attributes and runs the hooks registered with AddHook, so
+// that every variant constructs errors the same way.
+func wrap(err error, info string, decorate func(errorImpl) Error) Error {
+	return wrapOp(4, getCallingFunc(3), err, info, decorate)
+}
+
+// wrapOp implements wrap with the given op, recording the stack and caller
+// skip frames above wrapAt like wrapAt.
+func wrapOp(skip int, op string, err error, info string, decorate func(errorImpl) Error) Error {
+	err = convert(err)
+	wrapped := wrapAt(skip, op, err, err)
+	wrapped.info = info
+	if decorate == nil {
+		return hooks.run(wrapped)
+	}
+	return hooks.run(decorate(wrapped))
+}
+
+// firstInfo returns the first of optionalInfo, if any.
+func firstInfo(optionalInfo []string) string {
+	if len(optionalInfo) == 0 {
+		return ""
+	}
+	return optionalInfo[0]
+}
+
+// wrapImpl constructs the errorImpl wrapping err. innerErr is err with any
+// additional info from the wrap site which is not stored in info.
+func wrapImpl(op string, err, innerErr error) errorImpl {
+	return wrapAt(3, op, err, innerErr)
+}
+
+// wrapAt implements wrapImpl, recording the stack and caller skip frames
+// above wrapAt.
+func wrapAt(skip int, op string, err, innerErr error) errorImpl {
 	wrapped := errorImpl{
-		op:         getCallingFunc(2),
-		err:        fmt.Errorf("(%v): %w", fmt.Sprintf(fmtInfo, args...), err), // localizer.Ignore
-		stacktrace: ErrorStacktrace(err),
+		op:    op,
+		err:   innerErr,
+		stack: innerStack(err),
+	}
+
+	if ErrorID(err) == "" {
+		wrapped.id = generateID()
+	}
+	if name := currentSource(); name != "" && Source(err) == "" {
+		wrapped.source = name
+	}
+
+	// errors.Is and errors.As do not terminate on cyclic chains.
+	if !truncated(err) {
+		if code := ErrorCode(err); code == "" {
+			wrapped.code = classify(err)
+		} else {
+			wrapped.code = promotedCode(op, code)
+		}
+
+		if netErr, ok := asNetError(err); ok {
+			wrapped.timeout = netErr.Timeout()
+			wrapped.temporary = netErr.Temporary()
+		}
 	}
 
-	if wrapped.stacktrace == "" {
-		wrapped.stacktrace = string(debug.Stack())
+	if wrapped.stack == nil {
+		wrapped.stack = unsampledStack
+		if sampled(wrapped.code) {
+			wrapped.stack = callers(skip)
+		}
+	}
+	if wrapped.stack != unsampledStack {
+		wrapped.created = timestamp()
 	}
+	wrapped.caller = recordCaller(skip)
 
 	return wrapped
 }
 
+// AsError returns the outermost Error in the chain of err, even if errors of
+// other types wrap it, so that its methods can be used directly. It behaves
+// like errors.As with a target of type Error, which is also supported.
+//
+// Usage:
+//
+//	if ee, ok := e.AsError(err); ok {
+//		log.Printf("code=%s id=%s", ee.ClientCode(), ee.ID())
+//	}
+func AsError(err error) (Error, bool) {
+	for err := range chain(err) {
+		switch x := err.(type) {
+		case Error:
+			return x, true
+		case interface{ As(interface{}) bool }, interface{ Unwrap() []error }:
+			var target Error
+			ok := errors.As(err, &target)
+			return target, ok
+		}
+	}
+	return nil, false
+}
+
+// Ops returns the name of every function recorded by NewError and Wrap in the
+// error chain, ordered from outermost to innermost.
+func Ops(err error) []string {
+	var ops []string
+	for err := range chain(err) {
+		if e, ok := asImpl(err); ok && e.op != "" {
+			ops = append(ops, e.op)
+		}
+	}
+	return ops
+}
+
+// Infos returns the optional info passed to Wrap, Wrapf, WrapCtx and WrapLazy
+// in the error chain, ordered from outermost to innermost.
+func Infos(err error) []string {
+	var infos []string
+	for err := range chain(err) {
+		if e, ok := asImpl(err); ok && e.info != "" {
+			infos = append(infos, e.info)
+		}
+		if l, ok := err.(*lazyError); ok && l.err != nil {
+			infos = append(infos, l.text.String())
+		}
+	}
+	return infos
+}
+
 // errorImpl should always have a non-nil nested err and therefore this type
 // cannot by itself be the true root of an error stack.
 type errorImpl struct {
 	// Operation being performed--populated at runtime automagically
 	op string
 
+	// Additional context from the wrap site. Printed in parentheses after
+	// the op and code.
+	info string
+
 	// Represents the error type to be used by client or application.
 	// e.g. "unexpected_error", "database_error", "not_exists" etc.
 	// Use ErrorCode(err) to retrieve the outermost code.
 	code string
 
+	// Broad category of the error, orthogonal to code.
+	// Use ErrorKind(err) to retrieve the outermost kind.
+	kind Kind
+
 	// A user-friendly error message. Does not get printed with Error().
 	// Use ErrorMessage(err) to retrieve the outermost message.
 	message string
 
+	// Actionable remediation for the user. Does not get printed with Error().
+	// Use ErrorHint(err) to retrieve the outermost hint.
+	hint string
+
+	// Whether the messages of the wrapped errors are hidden from
+	// ErrorMessage(err) and Messages(err). Set by ClearMessages.
+	hidesMessages bool
+
+	// Whether the failed operation can be retried.
+	// Use IsRetryable(err) to check the whole error chain.
+	retryable bool
+
+	// Whether retryable was set with SetRetryable or SetRetryAfter, which
+	// takes precedence over the retryability registered for the code.
+	retryableSet bool
+
+	// How long to wait before retrying.
+	// Use ErrorRetryAfter(err) to retrieve the outermost duration.
+	retryAfter time.Duration
+
+	// Unique id of this error occurrence.
+	// Use ErrorID(err) to retrieve the outermost id.
+	id string
+
+	// Service or component which created the error, set with SetSource.
+	// Use Source(err) to retrieve the innermost source.
+	source string
+
+	// Time the error was created. Only recorded if enabled with SetTimestamps.
+	// Use ErrorTimestamp(err) and ErrorRootTimestamp(err) to retrieve it.
+	created time.Time
+
+	// File and line of the NewError or Wrap call. Only recorded if enabled
+	// with SetCallers. Use Callers(err) to retrieve them for the whole chain.
+	caller frame
+
+	// Implement net.Error so that existing type assertions keep working
+	// when errors are wrapped. Inferred from wrapped errors by Wrap.
+	timeout   bool
+	temporary bool
+
+	// External dependency which caused the error.
+	// Use Upstream(err) to retrieve the outermost upstream.
+	// Held by pointer so that errorImpl stays comparable for errors.Is.
+	upstream *UpstreamInfo
+
+	// Structured key-value pairs. Does not get printed with Error().
+	// Use ErrorFields(err) to retrieve the fields of the whole chain.
+	// Held by pointer so that errorImpl stays comparable for errors.Is.
+	fields *map[string]interface{}
+
+	// Typed payloads added with Attach. Does not get printed with Error().
+	// Use Detail(err) to retrieve them by type.
+	// Held by pointer so that errorImpl stays comparable for errors.Is.
+	details *[]interface{}
+
 	// Nested error for building an error stacktrace. Should not be nil.
 	err error
 
 	// Internal stacktrace for logging. Does not get printed with Error().
 	// Use ErrorStacktrace(err) to retrieve the innermost stacktrace.
-	stacktrace string
+	// Formatted lazily and shared with the errors wrapping this error.
+	stack *stack
 }
 
 func (e errorImpl) Error() string {
-	var sb strings.Builder
-	if e.op != "" {
-		sb.WriteString(fmt.Sprintf("%s: ", e.op))
+	if publicMode.Load() {
+		return PublicString(e)
+	}
+
+	// The prefixes of consecutive errorImpls are written into a builder sized
+	// up front rather than by recursing into Error() of every layer. The text
+	// of the first other error ends the string.
+	f := currentFormatter()
+	size, rest, cut := e.prefixLen(f), "", true
+	shown, more := 1, 0
+	for err := range chainAt(e.err, 1) {
+		if impl, ok := asImpl(err); ok {
+			if f.MaxOps > 0 && shown >= f.MaxOps {
+				more++
+			} else {
+				size += impl.prefixLen(f)
+				shown++
+			}
+			continue
+		}
+		// Rendering the rest of the chain could recurse forever.
+		if !truncated(err) {
+			rest, cut = err.Error(), false
+		}
+		break
+	}
+	if cut {
+		rest = "..."
+	}
+	var collapsed string
+	if more > 0 {
+		collapsed = f.collapsed(more)
 	}
-	if e.code != "" {
-		sb.WriteString(fmt.Sprintf("[%s] ", e.code)) // localizer.Ignore
+
+	var sb strings.Builder
+	sb.Grow(size + len(collapsed) + len(rest))
+	e.writePrefix(&sb, f)
+	written := 1
+	for err := range chainAt(e.err, 1) {
+		impl, ok := asImpl(err)
+		if !ok || written == shown {
+			break
+		}
+		impl.writePrefix(&sb, f)
+		written++
 	}
-	sb.WriteString(e.err.Error())
+	sb.WriteString(collapsed)
+	sb.WriteString(rest)
 
-	return sb.String()
+	return redact(sb.String())
 }
 
 func (e errorImpl) Unwrap() error {
@@ -195,30 +600,160 @@ func (e errorImpl) SetCode(code string) Error {
 	return e
 }
 
+func (e errorImpl) SetKind(kind Kind) Error {
+	e.kind = kind
+	return e
+}
+
+func (e errorImpl) Kind() Kind {
+	return e.kind
+}
+
 func (e errorImpl) SetMessage(message string) Error {
 	e.message = message
 	return e
 }
 
+func (e errorImpl) SetHint(hint string) Error {
+	e.hint = hint
+	return e
+}
+
+func (e errorImpl) Hint() string {
+	return e.hint
+}
+
+func (e errorImpl) SetRetryable(retryable bool) Error {
+	e.retryable = retryable
+	e.retryableSet = true
+	return e
+}
+
+func (e errorImpl) Retryable() bool {
+	return e.retryable
+}
+
+func (e errorImpl) SetRetryAfter(d time.Duration) Error {
+	e.retryAfter = d
+	e.retryable = true
+	e.retryableSet = true
+	return e
+}
+
+func (e errorImpl) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func (e errorImpl) SetField(key string, value interface{}) Error {
+	// copy so that errors sharing the same map are not affected
+	fields := make(map[string]interface{}, len(e.Fields())+1)
+	for k, v := range e.Fields() {
+		fields[k] = v
+	}
+	fields[key] = value
+	e.fields = &fields
+	return e
+}
+
+func (e errorImpl) Fields() map[string]interface{} {
+	if e.fields == nil {
+		return nil
+	}
+	return *e.fields
+}
+
+// fieldsRef returns a reference to fields suitable for errorImpl.
+func fieldsRef(fields map[string]interface{}) *map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &fields
+}
+
+func (e errorImpl) SetTimeout(timeout bool) Error {
+	e.timeout = timeout
+	return e
+}
+
+func (e errorImpl) Timeout() bool {
+	return e.timeout
+}
+
+func (e errorImpl) SetTemporary(temporary bool) Error {
+	e.temporary = temporary
+	return e
+}
+
+func (e errorImpl) Temporary() bool {
+	return e.temporary
+}
+
+func (e errorImpl) SetID(id string) Error {
+	e.id = id
+	return e
+}
+
+func (e errorImpl) ID() string {
+	return e.id
+}
+
+func (e errorImpl) With(optionalInfo ...string) Error {
+	return wrap(e, firstInfo(optionalInfo), nil)
+}
+
+func (e errorImpl) Timestamp() time.Time {
+	return e.created
+}
+
 func (e errorImpl) Stacktrace() string {
-	return e.stacktrace
+	return e.stack.String()
+}
+
+// impl is promoted to types embedding errorImpl (e.g. ValidationError) so
+// they are treated like errorImpl when inspecting the error chain.
+func (e errorImpl) impl() errorImpl {
+	return e
+}
+
+// asImpl returns the errorImpl of err if err is or embeds errorImpl.
+func asImpl(err error) (errorImpl, bool) {
+	if impl, ok := err.(errorImpl); ok {
+		return impl, true
+	}
+	if i, ok := err.(interface{ impl() errorImpl }); ok {
+		return i.impl(), true
+	}
+	return errorImpl{}, false
 }
 
 // getCallingFunc returns the name of the calling function N levels
 // above getCallingFunc (e.g. 0 for `getCallingFunc` itself)
 func getCallingFunc(frameOffset int) string {
 	// only need len = 1 to contain the calling function
-	programCounters := make([]uintptr, 1)
+	var programCounters [1]uintptr
 	// base offset is 1 to skip `runtime.Callers` itself
-	n := runtime.Callers(1+frameOffset, programCounters)
+	n := runtime.Callers(1+frameOffset, programCounters[:])
 	if n == 0 {
 		return "unknown"
 	}
-	frames := runtime.CallersFrames(programCounters)
-	frame, _ := frames.Next()
+	// FuncForPC avoids the allocation of runtime.CallersFrames. The return
+	// address is decremented so that it points into the calling function.
+	fn := runtime.FuncForPC(programCounters[0] - 1)
+	if fn == nil {
+		return "unknown"
+	}
+
+	return shortFuncName(fn.Name())
+}
 
-	// Remove package name (too verbose)
-	ss := strings.Split(frame.Function, "/")
-	funcname := ss[len(ss)-1]
-	return strings.SplitAfterN(funcname, ".", 2)[1]
+// shortFuncName removes the package name (too verbose) from the full name of a
+// function.
+func shortFuncName(funcname string) string {
+	if i := strings.LastIndexByte(funcname, '/'); i >= 0 {
+		funcname = funcname[i+1:]
+	}
+	if i := strings.IndexByte(funcname, '.'); i >= 0 {
+		funcname = funcname[i+1:]
+	}
+	return funcname
 }
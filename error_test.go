@@ -1,8 +1,15 @@
 package e
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -210,6 +217,13 @@ func TestErrorMessage(t *testing.T) {
 			},
 			want: "wrapped by fmt.Errorf",
 		},
+		{
+			name: "works with foreign HasMessage",
+			fn: func() string {
+				return ErrorMessage(Wrap(libError{code: "rate_limited", msg: "Slow down"}))
+			},
+			want: "Slow down",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -220,6 +234,30 @@ func TestErrorMessage(t *testing.T) {
 	}
 }
 
+func TestMessages(t *testing.T) {
+	err := Wrap(Wrap(NewError(CodeInvalid, "card 42 declined").SetMessage("Card declined"))).
+		SetMessage("Payment failed")
+	err = Wrap(err).SetMessage("Payment failed")
+
+	want := []string{"Payment failed", "Payment failed", "Card declined"}
+	if got := Messages(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := Messages(Foo()); got != nil {
+		t.Errorf("expected no messages but got %q", got)
+	}
+
+	SetMessageMerging(true)
+	t.Cleanup(func() { SetMessageMerging(false) })
+
+	if got, want := ErrorMessage(err), "Payment failed: Card declined"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorMessage(Foo()); got != "" {
+		t.Errorf("expected no message but got %q", got)
+	}
+}
+
 func TestErrorCode(t *testing.T) {
 	tests := []struct {
 		name string
@@ -288,6 +326,14 @@ func TestErrorCode(t *testing.T) {
 			},
 			want: CodeInternal,
 		},
+		{
+			name: "works with foreign HasCode",
+			fn: func() string {
+				err := fmt.Errorf("wrapped: %w", libError{code: "rate_limited"})
+				return ErrorCode(Wrap(err))
+			},
+			want: "rate_limited",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -298,6 +344,32 @@ func TestErrorCode(t *testing.T) {
 	}
 }
 
+// libError is defined by a library which does not import package e.
+type libError struct {
+	code string
+	msg  string
+}
+
+func (l libError) Error() string         { return "lib: " + l.code }
+func (l libError) ErrCode() string       { return l.code }
+func (l libError) ClientMessage() string { return l.msg }
+
+func TestCodes(t *testing.T) {
+	err := Wrap(Wrap(Foo()).SetCode(CodeInternal))
+	err = Wrap(fmt.Errorf("not encouraged but compatible: %w", err)).SetCode(CodeInternal)
+
+	want := []string{CodeInternal, CodeInternal, CodeDatabase}
+	if got := Codes(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := slices.Compact(Codes(err)), []string{CodeInternal, CodeDatabase}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := Codes(errors.New("foreign")); got != nil {
+		t.Errorf("expected no codes but got %q", got)
+	}
+}
+
 func TestErrorStack(t *testing.T) {
 	t.Run("ErrorStacktrace returns something", func(t *testing.T) {
 		err := NewError("", "unexpected error occurred")
@@ -308,16 +380,26 @@ func TestErrorStack(t *testing.T) {
 	t.Run("ErrorStacktrace returns inner stacktrace", func(t *testing.T) {
 		err := NewError("", "unexpected error occurred")
 		badError := errorImpl{
-			op:         "BAD",
-			code:       "BAD",
-			message:    "BAD",
-			err:        err,
-			stacktrace: "BAD",
+			op:      "BAD",
+			code:    "BAD",
+			message: "BAD",
+			err:     err,
+			stack:   stackText("BAD"),
 		}
 		if ErrorStacktrace(badError) == "BAD" {
 			t.Fatalf("expected inner stacktrace from ErrorStacktrace() but got outer")
 		}
 	})
+	t.Run("ErrorStacktrace starts at the caller and is shared by wrappers", func(t *testing.T) {
+		err := NewError("", "unexpected error occurred")
+		stack := ErrorStacktrace(err)
+		if !strings.HasPrefix(stack, "github.com/kisunji/e.TestErrorStack.func3()\n") {
+			t.Errorf("expected stacktrace to start at the caller but got %q", stack)
+		}
+		if got := ErrorStacktrace(Wrap(Wrap(err))); got != stack {
+			t.Errorf("\ngot:  %q\nwant: %q", got, stack)
+		}
+	})
 }
 
 func Benchmark_getCallingFunc(b *testing.B) {
@@ -355,4 +437,395 @@ func Test_getCallingFunc(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "unset retryable returns false",
+			err:  NewError(CodeUnexpected, "unexpected error occurred"),
+			want: false,
+		},
+		{
+			name: "set retryable returns true",
+			err:  NewError(CodeUnexpected, "unexpected error occurred").SetRetryable(true),
+			want: true,
+		},
+		{
+			name: "inner retryable is found through wraps",
+			err:  Wrap(fmt.Errorf("wrapped: %w", Wrap(errors.New("conn reset")).SetRetryable(true))),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	t.Run("unset fields returns nil", func(t *testing.T) {
+		if got := ErrorFields(Foo()); got != nil {
+			t.Errorf("expected nil but got %v", got)
+		}
+	})
+	t.Run("outer fields override inner fields", func(t *testing.T) {
+		inner := NewError(CodeDatabase, "cannot foo").SetField("id", 1).SetField("table", "bar")
+		outer := Wrap(fmt.Errorf("wrapped: %w", inner)).SetField("id", 2)
+
+		got := ErrorFields(outer)
+		if got["id"] != 2 || got["table"] != "bar" {
+			t.Errorf("unexpected fields: %v", got)
+		}
+	})
+	t.Run("errors with fields work with errors.Is", func(t *testing.T) {
+		sentinel := NewError(CodeDatabase, "cannot foo").SetField("id", 1)
+		if !errors.Is(Wrap(sentinel), sentinel) {
+			t.Errorf("expected errors.Is to match sentinel with fields")
+		}
+	})
+	t.Run("SetField does not modify original error", func(t *testing.T) {
+		err := NewError(CodeDatabase, "cannot foo").SetField("id", 1)
+		_ = err.SetField("id", 2)
+		if got := ErrorFields(err)["id"]; got != 1 {
+			t.Errorf("\ngot:  %v\nwant: %v", got, 1)
+		}
+	})
+	t.Run("ErrorFieldsAt returns the fields of a single layer", func(t *testing.T) {
+		inner := NewError(CodeDatabase, "cannot foo").SetField("id", 1).SetField("table", "bar")
+		outer := Wrap(fmt.Errorf("wrapped: %w", inner)).SetField("id", 2)
+
+		tests := []struct {
+			depth int
+			want  map[string]interface{}
+		}{
+			{-1, nil},
+			{0, map[string]interface{}{"id": 2}},
+			{1, nil},
+			{2, map[string]interface{}{"id": 1, "table": "bar"}},
+			{3, nil},
+			{4, nil},
+		}
+		for _, tt := range tests {
+			if got := ErrorFieldsAt(outer, tt.depth); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("depth %d\ngot:  %v\nwant: %v", tt.depth, got, tt.want)
+			}
+		}
+
+		ErrorFieldsAt(outer, 0)["id"] = 3
+		if got := ErrorFields(outer)["id"]; got != 2 {
+			t.Errorf("expected ErrorFieldsAt to return a copy but got %v", got)
+		}
+	})
+}
+
+func TestNetError(t *testing.T) {
+	t.Run("inferred from wrapped net.Error", func(t *testing.T) {
+		var netErr net.Error
+		if !errors.As(Wrap(timeoutError{}), &netErr) {
+			t.Fatalf("expected wrapped error to implement net.Error")
+		}
+		if !netErr.Timeout() || !netErr.Temporary() {
+			t.Errorf("expected Timeout() and Temporary() to be inferred")
+		}
+	})
+	t.Run("inferred through non-pkg wrapping", func(t *testing.T) {
+		err := Wrap(fmt.Errorf("dial: %w", timeoutError{}))
+		if !err.(net.Error).Timeout() {
+			t.Errorf("expected Timeout() to be inferred")
+		}
+	})
+	t.Run("set explicitly", func(t *testing.T) {
+		err := NewError(CodeUnexpected, "slow").SetTimeout(true).SetTemporary(true)
+		netErr := err.(net.Error)
+		if !netErr.Timeout() || !netErr.Temporary() {
+			t.Errorf("expected Timeout() and Temporary() to be set")
+		}
+	})
+}
+
+func TestErrorIsImmutable(t *testing.T) {
+	base := NewError(CodeDatabase, "cannot foo").SetField("id", 1)
+	want := base.Error()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = base.SetCode(CodeInternal).SetMessage("oh no").SetField("id", i).SetRetryable(true)
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = base.Error()
+			_ = ErrorFields(base)
+		}()
+	}
+	wg.Wait()
+
+	if base.Error() != want || ErrorMessage(base) != "" || IsRetryable(base) || ErrorFields(base)["id"] != 1 {
+		t.Errorf("expected setters not to modify the receiver")
+	}
+}
+
+func TestWrapNil(t *testing.T) {
+	succeed := func() error { return nil }
+	wrap := func() error { return Wrap(succeed()) }
+	wrapf := func() error { return Wrapf(succeed(), "id: %d", 1) }
+	wrapw := func() error { return Wrapw(succeed(), "id", 1) }
+	wrapCtx := func() error { return WrapCtx(context.Background(), succeed()) }
+
+	for name, fn := range map[string]func() error{"Wrap": wrap, "Wrapf": wrapf, "Wrapw": wrapw, "WrapCtx": wrapCtx} {
+		if err := fn(); err != nil {
+			t.Errorf("expected %s of nil error to return nil but got %#v", name, err)
+		}
+	}
+}
+
+func TestInfos(t *testing.T) {
+	err := WrapLazy(Wrapf(Wrap(Foo()), "bar id: %d", 1), func() string { return "lazy" })
+	err = Wrap(err, "outer")
+
+	want := []string{"outer", "lazy", "bar id: 1"}
+	if got := Infos(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := Infos(Foo()); got != nil {
+		t.Errorf("expected no infos but got %q", got)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantOps  []string
+		wantNone bool
+	}{
+		{
+			name:    "error",
+			err:     Fizz(),
+			wantOps: []string{"Fizz", "Foo"},
+		},
+		{
+			name:    "wrapped by fmt",
+			err:     fmt.Errorf("foreign: %w", Fizz()),
+			wantOps: []string{"Fizz", "Foo"},
+		},
+		{
+			name:    "joined",
+			err:     fmt.Errorf("foreign: %w", errors.Join(errors.New("other"), Bar())),
+			wantOps: []string{"Bar", "Foo"},
+		},
+		{
+			name:     "no error",
+			err:      fmt.Errorf("foreign: %w", errors.New("other")),
+			wantNone: true,
+		},
+		{
+			name:     "nil",
+			err:      nil,
+			wantNone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := AsError(tt.err)
+			if ok == tt.wantNone {
+				t.Fatalf("expected ok to be %v", !tt.wantNone)
+			}
+			if ok && !reflect.DeepEqual(Ops(got), tt.wantOps) {
+				t.Errorf("\ngot:  %q\nwant: %q", Ops(got), tt.wantOps)
+			}
+
+			var target Error
+			if errors.As(tt.err, &target) != ok {
+				t.Errorf("expected errors.As to agree with AsError")
+			}
+		})
+	}
+
+	t.Run("validation error", func(t *testing.T) {
+		got, ok := AsError(fmt.Errorf("foreign: %w", validateUser("", 1)))
+		if _, isValidation := got.(ValidationError); !ok || !isValidation {
+			t.Errorf("expected ValidationError but got %T", got)
+		}
+	})
+}
+
+func TestClearMessages(t *testing.T) {
+	inner := Wrap(NewError(CodeInvalid, "card 42 declined").SetMessage("Card 4242 declined by issuer"))
+
+	err := Wrap(ClearMessages(inner))
+	if got := ErrorMessage(err); got != "" {
+		t.Errorf("expected inner message to be hidden but got %q", got)
+	}
+	if got := ErrorMessage(inner.SetMessage("")); got == "" {
+		t.Errorf("expected SetMessage to only clear the outermost message")
+	}
+
+	err = ClearMessages(inner).SetMessage("Payment failed")
+	if got, want := ErrorMessage(err), "Payment failed"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := Messages(Wrap(err).SetMessage("Checkout failed")), []string{"Checkout failed", "Payment failed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	decoded, decodeErr := Decode(Encode(Wrap(ClearMessages(inner))))
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if got := ErrorMessage(decoded); got != "" {
+		t.Errorf("expected inner message to stay hidden after decoding but got %q", got)
+	}
+	if ClearMessages(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+func TestSetMessagePolicy(t *testing.T) {
+	SetMessagePolicy(func(code, msg string) string {
+		if code == CodeInternal {
+			return "Something went wrong"
+		}
+		return msg
+	})
+	t.Cleanup(func() { SetMessagePolicy(nil) })
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "rewritten",
+			err:  Wrap(NewError(CodeInternal, "nil pointer").SetMessage("Cannot read config.yaml")),
+			want: "Something went wrong",
+		},
+		{
+			name: "default for code",
+			err:  errSentinel,
+			want: "Something went wrong",
+		},
+		{
+			name: "kept",
+			err:  NewError(CodeInvalid, "bad email").SetMessage("Email is invalid"),
+			want: "Email is invalid",
+		},
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorMessage(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapw(t *testing.T) {
+	tests := []struct {
+		name          string
+		keysAndValues []interface{}
+		want          string
+		wantFields    map[string]interface{}
+	}{
+		{
+			name:          "key-value pairs",
+			keysAndValues: []interface{}{"attempt", 3, "host", "db1"},
+			want:          "TestWrapw.func1: (attempt=3 host=db1): Foo: [database_error] cannot foo",
+			wantFields:    map[string]interface{}{"attempt": 3, "host": "db1"},
+		},
+		{
+			name:          "quoted values",
+			keysAndValues: []interface{}{"query", "select 1", "empty", ""},
+			want:          `TestWrapw.func1: (query="select 1" empty=""): Foo: [database_error] cannot foo`,
+			wantFields:    map[string]interface{}{"query": "select 1", "empty": ""},
+		},
+		{
+			name:          "missing keys",
+			keysAndValues: []interface{}{42, "host"},
+			want:          "TestWrapw.func1: (!BADKEY=42 !BADKEY=host): Foo: [database_error] cannot foo",
+			wantFields:    map[string]interface{}{"!BADKEY": "host"},
+		},
+		{
+			name:          "secret",
+			keysAndValues: []interface{}{"token", Secret("hunter2")},
+			want:          "TestWrapw.func1: (token=[REDACTED]): Foo: [database_error] cannot foo",
+			wantFields:    map[string]interface{}{"token": Secret("hunter2")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Wrapw(Foo(), tt.keysAndValues...)
+			if got := err.Error(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+			if got := ErrorFields(err); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	err := Foo().(Error).SetMessage("Try again later").With("bar id: 1").With()
+
+	want := "TestWith: TestWith: (bar id: 1): Foo: [database_error] cannot foo"
+	if got := err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorCode(err); got != CodeDatabase {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeDatabase)
+	}
+	if got := ErrorMessage(err); got != "Try again later" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "Try again later")
+	}
+
+	v := NewValidation().AddField("email", "must be valid").With()
+	if _, ok := v.Unwrap().(ValidationError); !ok {
+		t.Errorf("expected ValidationError to be wrapped but got %#v", v.Unwrap())
+	}
+}
+
+func TestErrorHint(t *testing.T) {
+	err := Wrap(Wrap(Foo()).SetHint("check IAM permissions"))
+	if got := ErrorHint(err); got != "check IAM permissions" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "check IAM permissions")
+	}
+	if got := ErrorHint(Foo()); got != "" {
+		t.Errorf("expected no hint but got %q", got)
+	}
+	if got, want := fmt.Sprintf("%+v", err), err.Error()+"\nhint: check IAM permissions\n"+strings.TrimSuffix(ErrorStacktrace(err), "\n"); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorHint(NewValidation().SetHint("see --help")); got != "see --help" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "see --help")
+	}
+
+	decoded, decodeErr := Decode(Encode(err))
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if got := ErrorHint(decoded); got != "check IAM permissions" {
+		t.Errorf("expected hint to survive Encode but got %q", got)
+	}
+
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, err)
+	if got := ErrorHint(FromHTTPResponse(rec.Result())); got != "check IAM permissions" {
+		t.Errorf("expected hint to survive WriteHTTP but got %q", got)
+	}
+}
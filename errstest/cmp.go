@@ -0,0 +1,27 @@
+package errstest
+
+import (
+	"github.com/google/go-cmp/cmp"
+)
+
+// Comparer returns a cmp.Option which compares errors structurally by the
+// layers of their chains (ops, codes, messages and causes) while ignoring
+// stacktraces and ids, so table tests can use cmp.Diff on expected errors.
+//
+// Usage:
+//
+//	want := e.Wrap(e.NewError(e.CodeNotFound, "cannot find bar"))
+//	if diff := cmp.Diff(want, got, errstest.Comparer()); diff != "" {
+//		t.Errorf("unexpected error (-want +got):\n%s", diff)
+//	}
+//
+// Note that ops are the names of the functions which created the errors, so
+// expected errors must be constructed in functions with the same names.
+func Comparer() cmp.Option {
+	return cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return Diff(x, y) == ""
+	})
+}
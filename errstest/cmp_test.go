@@ -0,0 +1,64 @@
+package errstest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kisunji/e"
+)
+
+func TestComparer(t *testing.T) {
+	e.SetIDGenerator(e.NewULID)
+	t.Cleanup(func() { e.SetIDGenerator(nil) })
+
+	tests := []struct {
+		name string
+		x, y error
+		want bool
+	}{
+		{
+			name: "same chain with different stacks and ids",
+			x:    bar(),
+			y:    func() error { return bar() }(),
+			want: true,
+		},
+		{
+			name: "different codes",
+			x:    bar(),
+			y:    e.Wrap(foo(), "bar id: 2hs8qh9").SetCode(e.CodeTimeout),
+			want: false,
+		},
+		{
+			name: "non-pkg errors",
+			x:    errors.New("a"),
+			y:    errors.New("a"),
+			want: true,
+		},
+		{
+			name: "nil and non-nil",
+			x:    nil,
+			y:    bar(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cmp.Equal(tt.x, tt.y, Comparer()); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparerInStructs(t *testing.T) {
+	type result struct {
+		Value int
+		Err   error
+	}
+	want := result{Value: 1, Err: bar()}
+	got := result{Value: 1, Err: bar()}
+	if diff := cmp.Diff(want, got, Comparer()); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+}
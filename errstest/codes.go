@@ -0,0 +1,51 @@
+package errstest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+// AssertHandlesAll fails the test if a code registered with e.RegisterCode is
+// not among handled, so that adding a code without handling it fails the tests
+// instead of falling through to a default at runtime. A code is also handled
+// if its namespace is, e.g. "storage" handles "storage.postgres.timeout".
+//
+// Usage:
+//
+//	var apiErrors = map[string]APIError{...}
+//
+//	func TestAPIErrors(t *testing.T) {
+//		errstest.AssertHandlesAll(t, slices.Collect(maps.Keys(apiErrors))...)
+//	}
+func AssertHandlesAll(t testing.TB, handled ...string) {
+	t.Helper()
+	covered := make(map[string]bool, len(handled))
+	for _, code := range handled {
+		covered[code] = true
+	}
+
+	var missing []string
+	for _, code := range e.RegisteredCodes() {
+		if !isHandled(code, covered) {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) > 0 {
+		t.Errorf("unhandled codes:\n%s", strings.Join(missing, "\n"))
+	}
+}
+
+func isHandled(code string, covered map[string]bool) bool {
+	for {
+		if covered[code] {
+			return true
+		}
+		i := strings.LastIndex(code, e.CodeSeparator)
+		if i < 0 {
+			return false
+		}
+		code = code[:i]
+	}
+}
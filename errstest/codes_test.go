@@ -0,0 +1,20 @@
+package errstest
+
+import (
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestAssertHandlesAll(t *testing.T) {
+	e.RegisterCode("quota_exceeded", e.CodeInfo{})
+	e.RegisterCode("storage.postgres.timeout", e.CodeInfo{})
+
+	AssertHandlesAll(t, "quota_exceeded", "storage", e.CodeNotFound)
+
+	r := &recorder{TB: t}
+	AssertHandlesAll(r, "storage.postgres")
+	if len(r.failures) != 1 {
+		t.Errorf("expected unhandled quota_exceeded to fail but got %q", r.failures)
+	}
+}
@@ -0,0 +1,121 @@
+// Package errstest provides test assertions for errors from package e with
+// readable failure output.
+package errstest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+// AssertCode fails the test if the first code of err is not code.
+func AssertCode(t testing.TB, err error, code string) {
+	t.Helper()
+	if got := e.ErrorCode(err); got != code {
+		t.Errorf("unexpected code for error %q\ngot:  %q\nwant: %q", err, got, code)
+	}
+}
+
+// AssertMessage fails the test if the first message of err is not msg.
+func AssertMessage(t testing.TB, err error, msg string) {
+	t.Helper()
+	if got := e.ErrorMessage(err); got != msg {
+		t.Errorf("unexpected message for error %q\ngot:  %q\nwant: %q", err, got, msg)
+	}
+}
+
+// AssertOps fails the test if the ops of err, from outermost to innermost, are
+// not ops.
+func AssertOps(t testing.TB, err error, ops ...string) {
+	t.Helper()
+	if diff := diffLines(ops, e.Ops(err)); diff != "" {
+		t.Errorf("unexpected ops for error %q (-want +got):\n%s", err, diff)
+	}
+}
+
+// AssertChain fails the test if the layers of err, from outermost to innermost,
+// are not want. Each layer is written the way e.FprintTree renders it, e.g.
+//
+//	errstest.AssertChain(t, err,
+//		"ServeHTTP",
+//		"(bar id: 2hs8qh9)",
+//		`Foo [database_error] "Please try again"`,
+//		"cannot find bar",
+//	)
+func AssertChain(t testing.TB, err error, want ...string) {
+	t.Helper()
+	if diff := diffLines(want, Chain(err)); diff != "" {
+		t.Errorf("unexpected chain for error %q (-want +got):\n%s", err, diff)
+	}
+}
+
+// AssertEqual fails the test if the layers of err are not the layers of want,
+// ignoring stacktraces and ids, and shows the layers which diverged.
+//
+// Note that ops are the names of the functions which created the errors, so
+// want must be constructed in functions with the same names.
+func AssertEqual(t testing.TB, err, want error) {
+	t.Helper()
+	if diff := Diff(want, err); diff != "" {
+		t.Errorf("unexpected error %q (-want +got):\n%s", err, diff)
+	}
+}
+
+// Diff returns a line-by-line diff of the layers of want and got, the way
+// e.FprintTree renders them, or an empty string if they are equal. Lines of
+// want are prefixed with "-" and lines of got with "+".
+//
+// Usage:
+//
+//	if diff := errstest.Diff(want, err); diff != "" {
+//		t.Errorf("unexpected error (-want +got):\n%s", diff)
+//	}
+func Diff(want, got error) string {
+	return diffLines(Chain(want), Chain(got))
+}
+
+// Chain returns the layers of err the way e.FprintTree renders them.
+func Chain(err error) []string {
+	var sb strings.Builder
+	_ = e.FprintTree(&sb, err)
+
+	var layers []string
+	for _, line := range strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n") {
+		if line = strings.TrimLeft(line, " "); line != "" {
+			layers = append(layers, strings.TrimPrefix(line, "└─ "))
+		}
+	}
+	return layers
+}
+
+// diffLines returns a line-by-line diff of want and got, or an empty string if
+// they are equal.
+func diffLines(want, got []string) string {
+	n := len(want)
+	if len(got) > n {
+		n = len(got)
+	}
+
+	var sb strings.Builder
+	equal := len(want) == len(got)
+	for i := 0; i < n; i++ {
+		switch {
+		case i < len(want) && i < len(got) && want[i] == got[i]:
+			fmt.Fprintf(&sb, "  %q\n", want[i])
+		default:
+			equal = false
+			if i < len(want) {
+				fmt.Fprintf(&sb, "- %q\n", want[i])
+			}
+			if i < len(got) {
+				fmt.Fprintf(&sb, "+ %q\n", got[i])
+			}
+		}
+	}
+	if equal {
+		return ""
+	}
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package errstest
+
+import (
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+// recorder captures failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, format)
+}
+
+func foo() error {
+	return e.NewError(e.CodeNotFound, "cannot find bar").SetMessage("Bar does not exist")
+}
+
+func bar() error {
+	return e.Wrap(foo(), "bar id: 2hs8qh9")
+}
+
+func TestAssertions(t *testing.T) {
+	err := bar()
+
+	AssertCode(t, err, e.CodeNotFound)
+	AssertMessage(t, err, "Bar does not exist")
+	AssertOps(t, err, "bar", "foo")
+	AssertChain(t, err,
+		"bar",
+		"(bar id: 2hs8qh9)",
+		`foo [not_found] "Bar does not exist"`,
+		"cannot find bar",
+	)
+}
+
+func TestAssertionsFail(t *testing.T) {
+	r := &recorder{TB: t}
+	err := bar()
+
+	AssertCode(r, err, e.CodeTimeout)
+	AssertMessage(r, err, "")
+	AssertOps(r, err, "bar")
+	AssertChain(r, err, "bar")
+	AssertEqual(r, err, foo())
+
+	if len(r.failures) != 5 {
+		t.Errorf("expected 5 failures but got %d", len(r.failures))
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines([]string{"a", "b"}, []string{"a", "c", "d"})
+	want := "  \"a\"\n- \"b\"\n+ \"c\"\n+ \"d\"\n"
+	if got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if diff := diffLines([]string{"a"}, []string{"a"}); diff != "" {
+		t.Errorf("expected no diff but got %q", diff)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	want := bar()
+	got := e.Wrap(foo(), "bar id: 2hs8qh9").SetCode(e.CodeTimeout)
+
+	diff := Diff(want, got)
+	wantDiff := "- \"bar\"\n+ \"TestDiff [timeout]\"\n  \"(bar id: 2hs8qh9)\"\n" +
+		"  \"foo [not_found] \\\"Bar does not exist\\\"\"\n  \"cannot find bar\"\n"
+	if diff != wantDiff {
+		t.Errorf("\ngot:  %q\nwant: %q", diff, wantDiff)
+	}
+	if diff := Diff(bar(), bar()); diff != "" {
+		t.Errorf("expected no diff but got %q", diff)
+	}
+}
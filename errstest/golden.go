@@ -0,0 +1,81 @@
+package errstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+// update is registered by this package, so test packages which use
+// AssertJSONGolden must not define their own -update flag.
+var update = flag.Bool("update", false, "update golden files of errstest.AssertJSONGolden")
+
+// goldenID replaces ids in golden files since they differ between runs.
+const goldenID = "<id>"
+
+// AssertJSONGolden fails the test if the response which e.WriteHTTP writes for
+// err does not match the golden file at path, so that API teams can lock down
+// their error response contracts. The golden file holds the status code and
+// the JSON body with volatile fields such as the id replaced by placeholders.
+// Run the tests with -update to write the golden file instead.
+//
+// Usage:
+//
+//	errstest.AssertJSONGolden(t, err, "testdata/not_found.json")
+//	// go test ./... -update
+func AssertJSONGolden(t testing.TB, err error, path string) {
+	t.Helper()
+	got, marshalErr := goldenJSON(err)
+	if marshalErr != nil {
+		t.Fatalf("cannot marshal error %q: %v", err, marshalErr)
+	}
+
+	if *update {
+		if mkdirErr := os.MkdirAll(filepath.Dir(path), 0o755); mkdirErr != nil {
+			t.Fatalf("cannot update golden file: %v", mkdirErr)
+		}
+		if writeErr := os.WriteFile(path, got, 0o644); writeErr != nil {
+			t.Fatalf("cannot update golden file: %v", writeErr)
+		}
+		return
+	}
+
+	want, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("cannot read golden file, run with -update to create it: %v", readErr)
+	}
+	if diff := diffLines(strings.Split(string(want), "\n"), strings.Split(string(got), "\n")); diff != "" {
+		t.Errorf("unexpected JSON for error %q (-want +got):\n%s", err, diff)
+	}
+}
+
+// goldenJSON returns the normalized response written by e.WriteHTTP for err.
+func goldenJSON(err error) ([]byte, error) {
+	rec := httptest.NewRecorder()
+	e.WriteHTTP(rec, err)
+
+	var body map[string]interface{}
+	if unmarshalErr := json.Unmarshal(rec.Body.Bytes(), &body); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if _, ok := body["id"]; ok {
+		body["id"] = goldenID
+	}
+
+	var golden bytes.Buffer
+	enc := json.NewEncoder(&golden)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	encodeErr := enc.Encode(struct {
+		Status int                    `json:"status"`
+		Body   map[string]interface{} `json:"body"`
+	}{rec.Code, body})
+	return golden.Bytes(), encodeErr
+}
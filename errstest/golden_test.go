@@ -0,0 +1,33 @@
+package errstest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestAssertJSONGolden(t *testing.T) {
+	err := e.Wrap(foo()).SetID("01J9Z3")
+	AssertJSONGolden(t, err, "testdata/not_found.json")
+
+	otherID := e.Wrap(foo()).SetID("01J9Z4")
+	otherMessage := e.Wrap(foo()).SetMessage("Bar is gone")
+
+	t.Run("update", func(t *testing.T) {
+		updating := *update
+		t.Cleanup(func() { *update = updating })
+
+		path := filepath.Join(t.TempDir(), "testdata", "err.json")
+		*update = true
+		AssertJSONGolden(t, err, path)
+		*update = false
+		AssertJSONGolden(t, otherID, path)
+
+		r := &recorder{TB: t}
+		AssertJSONGolden(r, otherMessage, path)
+		if len(r.failures) != 1 {
+			t.Errorf("expected changed message to fail but got %q", r.failures)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package errstest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+// Template describes the parts of an error which are expected by Match. Only
+// the fields which are set are compared, so assertions do not break when
+// unrelated parts of the error change.
+type Template struct {
+	// Op must be one of the ops of the error chain.
+	Op string
+	// Code must be the first code of the error chain.
+	Code string
+	// Kind must be the first kind of the error chain.
+	Kind e.Kind
+	// Message must be the message returned by e.ErrorMessage.
+	Message string
+	// Cause must be in the error chain according to errors.Is.
+	Cause error
+}
+
+// Match reports whether got is non-nil and matches every field of template
+// which is set.
+//
+// Usage:
+//
+//	if !errstest.Match(errstest.Template{Code: e.CodeNotFound, Cause: sql.ErrNoRows}, err) {
+//		t.Errorf("unexpected error %v", err)
+//	}
+func Match(template Template, got error) bool {
+	return got != nil && len(mismatches(template, got)) == 0
+}
+
+// AssertMatch fails the test if err does not match template, listing every
+// field which does not match.
+func AssertMatch(t testing.TB, err error, template Template) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("expected error matching %+v but got nil", template)
+		return
+	}
+	for _, mismatch := range mismatches(template, err) {
+		t.Errorf("unexpected %s for error %q", mismatch, err)
+	}
+}
+
+func mismatches(template Template, err error) []string {
+	var m []string
+	if ops := e.Ops(err); template.Op != "" && !slices.Contains(ops, template.Op) {
+		m = append(m, fmt.Sprintf("ops\ngot:  %q\nwant: %q among them", ops, template.Op))
+	}
+	if got := e.ErrorCode(err); template.Code != "" && got != template.Code {
+		m = append(m, fmt.Sprintf("code\ngot:  %q\nwant: %q", got, template.Code))
+	}
+	if got := e.ErrorKind(err); template.Kind != "" && got != template.Kind {
+		m = append(m, fmt.Sprintf("kind\ngot:  %q\nwant: %q", got, template.Kind))
+	}
+	if got := e.ErrorMessage(err); template.Message != "" && got != template.Message {
+		m = append(m, fmt.Sprintf("message\ngot:  %q\nwant: %q", got, template.Message))
+	}
+	if template.Cause != nil && !errors.Is(err, template.Cause) {
+		m = append(m, fmt.Sprintf("cause\nwant: %q in the chain", template.Cause))
+	}
+	return m
+}
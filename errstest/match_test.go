@@ -0,0 +1,45 @@
+package errstest
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		template Template
+		err      error
+		want     bool
+	}{
+		{"empty template", Template{}, bar(), true},
+		{"op", Template{Op: "foo"}, bar(), true},
+		{"code and message", Template{Code: e.CodeNotFound, Message: "Bar does not exist"}, bar(), true},
+		{"cause", Template{Code: e.CodeNotFound, Cause: fs.ErrNotExist}, e.Wrap(os.ErrNotExist), true},
+		{"wrong op", Template{Op: "baz"}, bar(), false},
+		{"wrong code", Template{Code: e.CodeTimeout}, bar(), false},
+		{"wrong kind", Template{Kind: e.KindClient}, bar(), false},
+		{"missing cause", Template{Cause: fs.ErrNotExist}, bar(), false},
+		{"nil error", Template{}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.template, tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertMatch(t *testing.T) {
+	AssertMatch(t, bar(), Template{Op: "bar", Code: e.CodeNotFound})
+
+	r := &recorder{TB: t}
+	AssertMatch(r, bar(), Template{Op: "baz", Code: e.CodeTimeout, Message: "Bar does not exist"})
+	if len(r.failures) != 2 {
+		t.Errorf("expected 2 failures but got %d", len(r.failures))
+	}
+}
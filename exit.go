@@ -0,0 +1,67 @@
+package e
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	exitCodesMu sync.RWMutex
+	exitCodes   = make(map[string]int)
+
+	// replaced in tests
+	osExit           = os.Exit
+	stderr io.Writer = os.Stderr
+)
+
+// RegisterExitCode maps code to the process exit status returned by ExitCode.
+func RegisterExitCode(code string, exit int) {
+	exitCodesMu.Lock()
+	defer exitCodesMu.Unlock()
+	exitCodes[code] = exit
+}
+
+// ExitCode returns the exit status registered for the first code of err.
+// Returns 0 if err is nil and 1 if no exit status was registered.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	exitCodesMu.RLock()
+	defer exitCodesMu.RUnlock()
+	if exit, ok := exitCodes[ErrorCode(err)]; ok {
+		return exit
+	}
+	return 1
+}
+
+// HandleMain prints the message of err (or Error() if there is no message), its
+// hint and its documentation URL, if any, to stderr and exits with
+// ExitCode(err). Does nothing if err is nil.
+//
+// Usage:
+//
+//	func main() {
+//		e.HandleMain(run())
+//	}
+func HandleMain(err error) {
+	if err == nil {
+		return
+	}
+
+	msg := ErrorMessage(err)
+	if msg == "" {
+		msg = err.Error()
+	}
+	fmt.Fprintln(stderr, msg)
+	if hint := ErrorHint(err); hint != "" {
+		fmt.Fprintln(stderr, "hint: "+hint)
+	}
+	if docURL := ErrorDocURL(err); docURL != "" {
+		fmt.Fprintln(stderr, "see: "+docURL)
+	}
+	osExit(ExitCode(err))
+}
@@ -0,0 +1,83 @@
+package e
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	RegisterExitCode(CodeInvalid, 2)
+	t.Cleanup(func() { delete(exitCodes, CodeInvalid) })
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "nil exits with 0",
+			err:  nil,
+			want: 0,
+		},
+		{
+			name: "unregistered code exits with 1",
+			err:  Foo(),
+			want: 1,
+		},
+		{
+			name: "registered code",
+			err:  Wrap(NewError(CodeInvalid, "bad flag")),
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %d\nwant: %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleMain(t *testing.T) {
+	origStderr, origExit := stderr, osExit
+	t.Cleanup(func() { stderr, osExit = origStderr, origExit })
+
+	var buf bytes.Buffer
+	var exited int
+	stderr, osExit = &buf, func(code int) { exited = code }
+
+	t.Run("prints message", func(t *testing.T) {
+		buf.Reset()
+		HandleMain(Wrap(Foo()).SetMessage("cannot reach database"))
+		if got := buf.String(); got != "cannot reach database\n" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "cannot reach database\n")
+		}
+		if exited != 1 {
+			t.Errorf("\ngot:  %d\nwant: %d", exited, 1)
+		}
+	})
+	t.Run("prints hint", func(t *testing.T) {
+		buf.Reset()
+		HandleMain(Wrap(Foo()).SetMessage("cannot reach database").SetHint("check DATABASE_URL"))
+		want := "cannot reach database\nhint: check DATABASE_URL\n"
+		if got := buf.String(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("falls back to Error()", func(t *testing.T) {
+		buf.Reset()
+		HandleMain(Foo())
+		if got := buf.String(); got != "Foo: [database_error] cannot foo\n" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "Foo: [database_error] cannot foo\n")
+		}
+	})
+	t.Run("nil does nothing", func(t *testing.T) {
+		buf.Reset()
+		exited = -1
+		HandleMain(nil)
+		if buf.Len() != 0 || exited != -1 {
+			t.Errorf("expected nil error to be ignored")
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package e
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+)
+
+// FieldExpectedCodes is the field which lists the expected codes of errors
+// reported by ExpectCode.
+const FieldExpectedCodes = "expected_codes"
+
+var checkExpectations atomic.Bool
+
+// SetExpectations enables the checks of ExpectCode. Checks are disabled by
+// default and enabled by ModeDevelopment and ModeTest, so that contracts are
+// verified during development and tests without any cost in production.
+func SetExpectations(enabled bool) {
+	checkExpectations.Store(enabled)
+}
+
+// ExpectCode wraps err like Wrap and checks the contract of a layer which
+// should only pass on errors with one of codes, e.g. a repository which only
+// returns CodeNotFound and CodeTimeout. A code is also expected if its
+// namespace is, e.g. "storage" expects "storage.postgres.timeout". If checks
+// are enabled with SetExpectations and ErrorCode of err is not expected, the
+// wrapped error is passed to Report with SeverityWarning and the
+// FieldExpectedCodes field. ExpectCode never panics; the wrapped error is
+// returned either way. Returns nil if err is nil.
+//
+// Usage:
+//
+//	bar, err := repo.FetchBar(ctx, id)
+//	if err != nil {
+//		return e.ExpectCode(err, e.CodeNotFound, e.CodeTimeout)
+//	}
+func ExpectCode(err error, codes ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := wrap(err, "", nil)
+	if wrapped != nil && checkExpectations.Load() && !isExpected(ErrorCode(wrapped), codes) {
+		unexpected := wrapped.SetField(FieldExpectedCodes, slices.Clone(codes))
+		Report(context.Background(), Attach(unexpected, CodeInfo{Severity: SeverityWarning}))
+	}
+	return wrapped
+}
+
+func isExpected(code string, codes []string) bool {
+	for _, expected := range codes {
+		if codeHasPrefix(code, canonicalCode(expected)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package e
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExpectCode(t *testing.T) {
+	t.Cleanup(resetReporters)
+	t.Cleanup(func() { SetExpectations(false) })
+
+	var reported []error
+	RegisterReporter(ReporterFunc(func(_ context.Context, err error, severity Severity) {
+		if severity != SeverityWarning {
+			t.Errorf("\ngot:  %v\nwant: %v", severity, SeverityWarning)
+		}
+		reported = append(reported, err)
+	}), SeverityInfo)
+
+	if err := ExpectCode(Foo(), CodeNotFound); len(reported) != 0 {
+		t.Errorf("expected disabled checks to not report but got %v", reported)
+	} else if got, want := err.Error(), "TestExpectCode: Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	SetExpectations(true)
+	if ExpectCode(nil, CodeNotFound) != nil {
+		t.Errorf("expected nil")
+	}
+	ExpectCode(NewError(CodeNotFound, "cannot find bar"), CodeNotFound, CodeTimeout)
+	ExpectCode(NewError("storage.postgres.timeout", "cannot query bar"), "storage")
+	if len(reported) != 0 {
+		t.Errorf("expected expected codes to not be reported but got %v", reported)
+	}
+
+	err := ExpectCode(Foo(), CodeNotFound, CodeTimeout)
+	if len(reported) != 1 {
+		t.Fatalf("expected 1 reported error but got %v", reported)
+	}
+	if got, want := reported[0].Error(), err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := ErrorFields(reported[0])[FieldExpectedCodes], []string{CodeNotFound, CodeTimeout}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+	if _, ok := ErrorFields(err)[FieldExpectedCodes]; ok {
+		t.Errorf("expected returned error to not carry the expected codes")
+	}
+}
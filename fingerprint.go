@@ -0,0 +1,71 @@
+package e
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// FingerprintOptions selects which parts of an error chain are hashed by
+// Fingerprint. Volatile data such as causes, messages and stacktraces are never
+// included so that identical failure paths produce identical fingerprints.
+type FingerprintOptions struct {
+	// Code includes the first code of the chain (see ErrorCode).
+	Code bool
+
+	// Ops includes the ordered ops of the chain (see Ops).
+	Ops bool
+
+	// RootType includes the Go type of the innermost error, e.g. "*pq.Error".
+	RootType bool
+
+	// Fields includes the values of the given field keys (see ErrorFields).
+	// Only keys with stable, low-cardinality values should be used.
+	Fields []string
+}
+
+// DefaultFingerprint is used by Fingerprint and hashes the code and ops.
+var DefaultFingerprint = FingerprintOptions{Code: true, Ops: true}
+
+// Fingerprint returns a short hash of the code and ordered ops of err so that
+// log aggregation and alerting can group errors with the same failure path.
+// Returns an empty string if err is nil.
+//
+// Use FingerprintOptions.Fingerprint to choose other components.
+func Fingerprint(err error) string {
+	return DefaultFingerprint.Fingerprint(err)
+}
+
+// Fingerprint returns a short hash of the components of err selected by opts.
+// Returns an empty string if err is nil.
+func (opts FingerprintOptions) Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	h := sha256.New()
+	if opts.Code {
+		fmt.Fprintf(h, "code=%q;", ErrorCode(err))
+	}
+	if opts.Ops {
+		for _, op := range Ops(err) {
+			fmt.Fprintf(h, "op=%q;", op)
+		}
+	}
+	if opts.RootType {
+		var root error
+		for root = range chain(err) {
+		}
+		fmt.Fprintf(h, "root=%T;", root)
+	}
+	if len(opts.Fields) > 0 {
+		fields := ErrorFields(err)
+		keys := append([]string(nil), opts.Fields...)
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "field.%s=%v;", k, fields[k])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
@@ -0,0 +1,45 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	fail := func(id string) error {
+		return Wrap(Wrap(Foo(), "id: "+id).SetField("id", id))
+	}
+
+	t.Run("same failure path has same fingerprint", func(t *testing.T) {
+		if Fingerprint(fail("1")) != Fingerprint(fail("2")) {
+			t.Errorf("expected fingerprints to ignore info and fields")
+		}
+	})
+	t.Run("different code has different fingerprint", func(t *testing.T) {
+		if Fingerprint(fail("1")) == Fingerprint(Wrap(fail("1")).SetCode(CodeInternal)) {
+			t.Errorf("expected fingerprints to differ")
+		}
+	})
+	t.Run("different ops have different fingerprint", func(t *testing.T) {
+		if Fingerprint(Bar()) == Fingerprint(Fizz()) {
+			t.Errorf("expected fingerprints to differ")
+		}
+	})
+	t.Run("configured fields are included", func(t *testing.T) {
+		opts := FingerprintOptions{Code: true, Fields: []string{"id"}}
+		if opts.Fingerprint(fail("1")) == opts.Fingerprint(fail("2")) {
+			t.Errorf("expected fingerprints to differ")
+		}
+	})
+	t.Run("root type is included", func(t *testing.T) {
+		opts := FingerprintOptions{RootType: true}
+		if opts.Fingerprint(Wrap(errors.New("a"))) == opts.Fingerprint(Wrap(timeoutError{})) {
+			t.Errorf("expected fingerprints to differ")
+		}
+	})
+	t.Run("nil returns blank", func(t *testing.T) {
+		if got := Fingerprint(nil); got != "" {
+			t.Errorf("expected blank but got %q", got)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package e
+
+import "time"
+
+// FlatError holds the attributes of an error chain in a single struct, as
+// returned by Flatten.
+type FlatError struct {
+	// Error is the InternalString of the chain, so that logs keep the full
+	// chain in public mode.
+	Error      string
+	Code       string
+	Kind       Kind
+	Message    string
+	Messages   []string
+	Hint       string
+	DocURL     string
+	ID         string
+	Source     string
+	Retryable  bool
+	Upstream   *UpstreamInfo
+	Timestamp  time.Time
+	Ops        []string
+	Infos      []string
+	Fields     map[string]interface{}
+	Stacktrace string
+	Stack      []Frame
+}
+
+// Flatten extracts the attributes of err which are reported by ToMap and the
+// structured logging integrations, so that they share one extraction path
+// instead of each calling the accessor functions. Attributes which are not
+// set are left empty. Returns the zero FlatError if err is nil.
+//
+// Usage:
+//
+//	flat := e.Flatten(err)
+//	span.SetAttributes(attribute.String("error.code", flat.Code))
+func Flatten(err error) FlatError {
+	if err == nil {
+		return FlatError{}
+	}
+	flat := FlatError{
+		Error:      InternalString(err),
+		Code:       ErrorCode(err),
+		Kind:       ErrorKind(err),
+		Message:    ErrorMessage(err),
+		Messages:   Messages(err),
+		Hint:       ErrorHint(err),
+		DocURL:     ErrorDocURL(err),
+		ID:         ErrorID(err),
+		Source:     Source(err),
+		Retryable:  IsRetryable(err),
+		Timestamp:  ErrorRootTimestamp(err),
+		Ops:        Ops(err),
+		Infos:      Infos(err),
+		Fields:     ErrorFields(err),
+		Stacktrace: ErrorStacktrace(err),
+	}
+	flat.Stack = parseFrames(flat.Stacktrace)
+	if upstream, ok := Upstream(err); ok {
+		flat.Upstream = &upstream
+	}
+	return flat
+}
@@ -0,0 +1,52 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlatten(t *testing.T) {
+	if got := Flatten(nil); !reflect.DeepEqual(got, FlatError{}) {
+		t.Errorf("expected zero FlatError but got %+v", got)
+	}
+
+	err := Wrap(NewError(CodeNotFound, "cannot find bar"), "bar id: 2hs8qh9").
+		SetMessage("Bar does not exist").
+		SetHint("Check the bar id").
+		SetKind(KindClient).
+		SetRetryAfter(time.Second).
+		SetField("bar_id", "2hs8qh9")
+
+	got := Flatten(err)
+	if got.Stacktrace == "" {
+		t.Errorf("expected stacktrace")
+	}
+	if len(got.Stack) == 0 || got.Stack[0].Function != "github.com/kisunji/e.TestFlatten" || got.Stack[0].Line == 0 {
+		t.Errorf("unexpected stack %+v", got.Stack)
+	}
+	got.Stacktrace, got.Stack = "", nil
+	want := FlatError{
+		Error:     "TestFlatten: (bar id: 2hs8qh9): TestFlatten: [not_found] cannot find bar",
+		Code:      CodeNotFound,
+		Kind:      KindClient,
+		Message:   "Bar does not exist",
+		Messages:  []string{"Bar does not exist"},
+		Hint:      "Check the bar id",
+		Retryable: true,
+		Ops:       []string{"TestFlatten", "TestFlatten"},
+		Infos:     []string{"bar id: 2hs8qh9"},
+		Fields:    map[string]interface{}{"bar_id": "2hs8qh9"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	t.Run("public mode", func(t *testing.T) {
+		SetPublicMode(true)
+		t.Cleanup(func() { SetPublicMode(false) })
+		if got, want := Flatten(Foo()).Error, "Foo: [database_error] cannot foo"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}
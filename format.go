@@ -0,0 +1,167 @@
+package e
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Formatter controls how Error() renders the ops, codes and infos of an error
+// chain. The zero value renders the default format:
+//
+//	Fizz: [database_error] (failed to fizz): Foo: [database_error] cannot foo
+type Formatter struct {
+	// Separator follows every op and info. Defaults to ": ".
+	Separator string
+
+	// CodeSuffix places codes after the op instead of before the info, e.g.
+	// "Fizz [database_error]: (failed to fizz): ".
+	CodeSuffix bool
+
+	// OmitOps leaves ops out of the output. Use Ops() to retrieve them.
+	OmitOps bool
+
+	// MaxOps collapses the errors beyond the first MaxOps ops of a chain into
+	// "... (+12 more)", keeping the root cause, so that deeply wrapped errors
+	// fit into log lines. Ops(), Encode() and FprintTree() still return the
+	// full chain. 0 means no limit.
+	MaxOps int
+}
+
+var formatter atomic.Pointer[Formatter]
+
+// SetFormatter changes how Error() of every Error is rendered outside of public
+// mode, e.g. to match the conventions of a log parser. Use Format to render a
+// single error differently. A zero Formatter restores the default format.
+//
+// Errors created by other packages, such as fmt.Errorf, render the errors they
+// wrap once when they are created and are not affected.
+func SetFormatter(f Formatter) {
+	formatter.Store(&f)
+}
+
+func currentFormatter() Formatter {
+	if f := formatter.Load(); f != nil {
+		return *f
+	}
+	return Formatter{}
+}
+
+// Format renders err like Error() does outside of public mode, using f instead
+// of the Formatter set with SetFormatter. Errors wrapped by other error types
+// are rendered with f too, as long as the text of the wrapper ends with the
+// text of the wrapped error. Returns an empty string if err is nil.
+//
+// Usage:
+//
+//	log.Print(e.Format(err, e.Formatter{Separator: " | ", OmitOps: true}))
+//	// "[database_error] (failed to fizz) | [database_error] cannot foo"
+func Format(err error, f Formatter) string {
+	var sb strings.Builder
+	shown, more := 0, 0
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok {
+			if f.MaxOps > 0 && shown == f.MaxOps {
+				more++
+				continue
+			}
+			impl.writePrefix(&sb, f)
+			shown++
+			continue
+		}
+		if l, ok := err.(*lazyError); ok && l.err != nil {
+			if more == 0 {
+				writeInfo(&sb, l.text.String(), f)
+			}
+			continue
+		}
+
+		text, separated := ownText(err, errors.Unwrap(err))
+		if separated && more > 0 {
+			continue
+		}
+		if more > 0 {
+			sb.WriteString(f.collapsed(more))
+		}
+		sb.WriteString(text)
+		if !separated {
+			break
+		}
+	}
+	return redact(sb.String())
+}
+
+func (f Formatter) separator() string {
+	if f.Separator == "" {
+		return ": "
+	}
+	return f.Separator
+}
+
+// collapsed returns the text which replaces n errors beyond MaxOps.
+func (f Formatter) collapsed(n int) string {
+	return "... (+" + strconv.Itoa(n) + " more)" + f.separator() // localizer.Ignore
+}
+
+// writePrefix writes the op, code and info which precede the nested error in
+// Error().
+func (e *errorImpl) writePrefix(sb *strings.Builder, f Formatter) {
+	op := e.op
+	if f.OmitOps {
+		op = ""
+	}
+
+	sb.WriteString(op)
+	if e.code != "" && f.CodeSuffix {
+		if op != "" {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("[") // localizer.Ignore
+		sb.WriteString(e.code)
+		sb.WriteString("]") // localizer.Ignore
+	}
+	if op != "" || (e.code != "" && f.CodeSuffix) {
+		sb.WriteString(f.separator())
+	}
+	if e.code != "" && !f.CodeSuffix {
+		sb.WriteString("[") // localizer.Ignore
+		sb.WriteString(e.code)
+		sb.WriteString("] ") // localizer.Ignore
+	}
+	writeInfo(sb, e.info, f)
+}
+
+// prefixLen returns the length of the text written by writePrefix.
+func (e *errorImpl) prefixLen(f Formatter) int {
+	n := 0
+	if !f.OmitOps && e.op != "" {
+		n += len(e.op) + len(f.separator())
+		if e.code != "" && f.CodeSuffix {
+			n += len(" ")
+		}
+	} else if e.code != "" && f.CodeSuffix {
+		n += len(f.separator())
+	}
+	if e.code != "" {
+		n += len(e.code) + len("[] ")
+		if f.CodeSuffix {
+			n -= len(" ")
+		}
+	}
+	if e.info != "" {
+		n += len(e.info) + len("()") + len(f.separator())
+	}
+	return n
+}
+
+// writeInfo writes additional info from a wrap site in parentheses.
+func writeInfo(sb *strings.Builder, info string, f Formatter) {
+	if info == "" {
+		return
+	}
+	sb.WriteString("(") // localizer.Ignore
+	sb.WriteString(info)
+	sb.WriteString(")") // localizer.Ignore
+	sb.WriteString(f.separator())
+}
@@ -0,0 +1,136 @@
+package e
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		f    Formatter
+		want string
+	}{
+		{
+			name: "default",
+			err:  Fizz(),
+			want: "Fizz: (failed to fizz): Foo: [database_error] cannot foo",
+		},
+		{
+			name: "separator",
+			err:  Fizz(),
+			f:    Formatter{Separator: " | "},
+			want: "Fizz | (failed to fizz) | Foo | [database_error] cannot foo",
+		},
+		{
+			name: "code suffix",
+			err:  Fizz(),
+			f:    Formatter{CodeSuffix: true},
+			want: "Fizz: (failed to fizz): Foo [database_error]: cannot foo",
+		},
+		{
+			name: "omit ops",
+			err:  Fizz(),
+			f:    Formatter{OmitOps: true},
+			want: "(failed to fizz): [database_error] cannot foo",
+		},
+		{
+			name: "omit ops with code suffix",
+			err:  Fizz(),
+			f:    Formatter{OmitOps: true, CodeSuffix: true},
+			want: "(failed to fizz): [database_error]: cannot foo",
+		},
+		{
+			name: "lazy info",
+			err:  WrapLazy(Foo(), func() string { return "lazy" }),
+			f:    Formatter{Separator: " > "},
+			want: "TestFormat > (lazy) > Foo > [database_error] cannot foo",
+		},
+		{
+			name: "fmt wrapped",
+			err:  FizzBuzz(),
+			f:    Formatter{Separator: " > "},
+			want: "FizzBuzz > not encouraged but compatible: Foo > [database_error] cannot foo",
+		},
+		{
+			name: "max ops",
+			err:  deepWrap(Foo(), 5),
+			f:    Formatter{MaxOps: 2},
+			want: "deepWrap: deepWrap: ... (+4 more): cannot foo",
+		},
+		{
+			name: "max ops with fmt wrapped",
+			err:  deepWrap(FizzBuzz(), 2),
+			f:    Formatter{MaxOps: 1},
+			want: "deepWrap: ... (+3 more): cannot foo",
+		},
+		{
+			name: "max ops beyond chain",
+			err:  Fizz(),
+			f:    Formatter{MaxOps: 2},
+			want: "Fizz: (failed to fizz): Foo: [database_error] cannot foo",
+		},
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.err, tt.f); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFormatter(t *testing.T) {
+	SetFormatter(Formatter{Separator: " | ", CodeSuffix: true})
+	t.Cleanup(func() { SetFormatter(Formatter{}) })
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "wrapped",
+			err:  FizzBuzzWhiz(),
+			want: "FizzBuzzWhiz [database_error] | (changed code to database) | badWrapper [internal_error] | (changed code to internal) | BADWRAPBar | Foo [database_error] | cannot foo",
+		},
+		{
+			name: "lazy info",
+			err:  WrapLazy(fmt.Errorf("foreign"), func() string { return "lazy" }),
+			want: "TestSetFormatter | (lazy) | foreign",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxOps(t *testing.T) {
+	SetFormatter(Formatter{MaxOps: 3})
+	t.Cleanup(func() { SetFormatter(Formatter{}) })
+
+	err := deepWrap(Fizz(), 12)
+	if got, want := err.Error(), "deepWrap: deepWrap: deepWrap: ... (+11 more): cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := len(Ops(err)), 14; got != want {
+		t.Errorf("\ngot:  %d\nwant: %d", got, want)
+	}
+}
+
+func deepWrap(err error, n int) error {
+	for i := 0; i < n; i++ {
+		err = Wrap(err)
+	}
+	return err
+}
@@ -1,3 +1,5 @@
 module github.com/kisunji/e
 
-go 1.14
+go 1.24
+
+require github.com/google/go-cmp v0.7.0
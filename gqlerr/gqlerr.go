@@ -0,0 +1,80 @@
+// Package gqlerr converts errors from package e into GraphQL errors which carry
+// the code in extensions, for use with gqlgen.
+package gqlerr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/kisunji/e"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// DefaultMessage is used as the GraphQL message of errors without a message.
+const DefaultMessage = "unexpected error"
+
+// Convert returns a GraphQL error at path whose message is the message of err
+// and whose extensions contain "code", "clientMessage" and "id" when set.
+// Internal details such as ops and causes are not included.
+func Convert(err error, path ast.Path) *gqlerror.Error {
+	if err == nil {
+		return nil
+	}
+
+	msg := e.ErrorMessage(err)
+	gqlErr := &gqlerror.Error{
+		Err:     err,
+		Message: msg,
+		Path:    path,
+	}
+	if gqlErr.Message == "" {
+		gqlErr.Message = DefaultMessage
+	}
+
+	extensions := make(map[string]interface{})
+	if code := e.ErrorCode(err); code != "" {
+		extensions["code"] = code
+	}
+	if msg != "" {
+		extensions["clientMessage"] = msg
+	}
+	if id := e.ErrorID(err); id != "" {
+		extensions["id"] = id
+	}
+	if len(extensions) > 0 {
+		gqlErr.Extensions = extensions
+	}
+	return gqlErr
+}
+
+// Presenter is a graphql.ErrorPresenterFunc which converts errors with Convert.
+// Errors which are already GraphQL errors keep their path and locations.
+//
+// Usage:
+//
+//	srv := handler.New(generated.NewExecutableSchema(cfg))
+//	srv.SetErrorPresenter(gqlerr.Presenter)
+func Presenter(ctx context.Context, err error) *gqlerror.Error {
+	if err == nil {
+		return nil
+	}
+
+	path := graphql.GetPath(ctx)
+	var locations []gqlerror.Location
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		// gqlgen wraps resolver errors with their path before presenting them
+		if gqlErr.Err == nil {
+			return gqlErr
+		}
+		err, path, locations = gqlErr.Err, gqlErr.Path, gqlErr.Locations
+	}
+
+	converted := Convert(err, path)
+	converted.Locations = locations
+	return converted
+}
+
+var _ graphql.ErrorPresenterFunc = Presenter
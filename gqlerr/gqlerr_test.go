@@ -0,0 +1,59 @@
+package gqlerr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kisunji/e"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestConvert(t *testing.T) {
+	path := ast.Path{ast.PathName("bar"), ast.PathIndex(0)}
+	err := e.NewError(e.CodeNotFound, "cannot find bar in table bars").SetMessage("Bar does not exist")
+
+	got := Convert(err, path)
+	if got.Message != "Bar does not exist" {
+		t.Errorf("\ngot:  %q\nwant: %q", got.Message, "Bar does not exist")
+	}
+	want := map[string]interface{}{"code": e.CodeNotFound, "clientMessage": "Bar does not exist"}
+	if !reflect.DeepEqual(got.Extensions, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got.Extensions, want)
+	}
+	if got.Path.String() != "bar[0]" {
+		t.Errorf("\ngot:  %q\nwant: %q", got.Path.String(), "bar[0]")
+	}
+}
+
+func TestPresenter(t *testing.T) {
+	t.Run("unwraps gqlgen path errors", func(t *testing.T) {
+		path := ast.Path{ast.PathName("bar")}
+		err := gqlerror.WrapPath(path, e.NewError(e.CodeNotFound, "cannot find bar"))
+
+		got := Presenter(context.Background(), err)
+		if got.Message != DefaultMessage {
+			t.Errorf("expected internal cause to be hidden but got %q", got.Message)
+		}
+		if got.Extensions["code"] != e.CodeNotFound {
+			t.Errorf("\ngot:  %v\nwant: %v", got.Extensions["code"], e.CodeNotFound)
+		}
+		if got.Path.String() != "bar" {
+			t.Errorf("\ngot:  %q\nwant: %q", got.Path.String(), "bar")
+		}
+	})
+	t.Run("GraphQL errors are passed through", func(t *testing.T) {
+		err := gqlerror.Errorf("syntax error")
+		if got := Presenter(context.Background(), err); got != err {
+			t.Errorf("expected %v but got %v", err, got)
+		}
+	})
+	t.Run("non-pkg errors get default message", func(t *testing.T) {
+		got := Presenter(context.Background(), errors.New("secret"))
+		if got.Message != DefaultMessage || got.Extensions != nil {
+			t.Errorf("unexpected error: %#v", got)
+		}
+	})
+}
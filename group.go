@@ -0,0 +1,107 @@
+package e
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Group runs functions in goroutines and collects all of their errors, like
+// errgroup.Group from golang.org/x/sync but without stopping at the first
+// failure. Every error is wrapped with the name of the function that returned
+// it. The zero value is ready to use. A Group must not be reused after Wait or
+// WaitAll returned.
+//
+// Usage:
+//
+//	var g e.Group
+//	for _, u := range users {
+//		g.Go(func() error {
+//			return save(u)
+//		})
+//	}
+//	return g.WaitAll()
+//	// "SaveAll: item 0: SaveAll.func1: save: [invalid] bad email; item 2: ..."
+type Group struct {
+	wg     sync.WaitGroup
+	cancel context.CancelCauseFunc
+
+	mu    sync.Mutex
+	items ItemErrors
+	n     int
+}
+
+// GroupWithContext returns a Group and a context derived from ctx which is
+// canceled when a function first fails or once Wait or WaitAll returns.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls fn in a new goroutine. Its error, if any, is labeled with the
+// order in which Go was called.
+func (g *Group) Go(fn func() error) {
+	g.mu.Lock()
+	index := g.n
+	g.n++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		err := fn()
+		if err == nil {
+			return
+		}
+		wrapped := wrapOp(3, funcOp(fn), err, "", nil)
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if len(g.items) == 0 && g.cancel != nil {
+			g.cancel(wrapped)
+		}
+		g.items = append(g.items, ItemError{Index: index, Err: wrapped})
+	}()
+}
+
+// Wait blocks until every function has returned and returns the first error
+// returned by them, like errgroup.Group. Returns nil if all of them succeeded.
+func (g *Group) Wait() Error {
+	g.wait()
+	if len(g.items) == 0 {
+		return nil
+	}
+	first, _ := g.items[0].Err.(Error)
+	return first
+}
+
+// WaitAll blocks until every function has returned and joins all of their
+// errors like WrapAll, ordered by the calls to Go. Returns nil if all of them
+// succeeded.
+func (g *Group) WaitAll() Error {
+	g.wait()
+	items := slices.Clone(g.items)
+	slices.SortFunc(items, func(a, b ItemError) int {
+		return a.Index - b.Index
+	})
+	return joinItems(getCallingFunc(2), items)
+}
+
+func (g *Group) wait() {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+}
+
+// funcOp returns the op recorded for errors returned by fn.
+func funcOp(fn func() error) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	return shortFuncName(f.Name())
+}
@@ -0,0 +1,66 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	g.Go(func() error { return Foo() })
+	g.Go(func() error { return nil })
+	g.Go(func() error { return errors.New("foreign") })
+
+	err := g.WaitAll()
+	want := "TestGroup: item 0: TestGroup.func1: Foo: [database_error] cannot foo; item 2: TestGroup.func3: foreign"
+	if got := err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	items := ErrorItems(err)
+	if got := ErrorCode(items[0].Err); got != CodeDatabase {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeDatabase)
+	}
+	if got := ErrorStacktrace(items[0].Err); got == "" {
+		t.Errorf("expected stacktrace to be kept")
+	}
+
+	t.Run("wait returns first error", func(t *testing.T) {
+		var g Group
+		g.Go(func() error { return Foo() })
+		if err := g.Wait(); ErrorCode(err) != CodeDatabase {
+			t.Errorf("expected first error but got %v", err)
+		}
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		var g Group
+		g.Go(func() error { return nil })
+		if err := g.WaitAll(); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+		if err := g.Wait(); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+}
+
+func TestGroupWithContext(t *testing.T) {
+	g, ctx := GroupWithContext(context.Background())
+	g.Go(func() error { return Foo() })
+	g.Go(func() error {
+		<-ctx.Done()
+		return Wrap(context.Cause(ctx))
+	})
+
+	err := g.WaitAll()
+	if got := len(ErrorItems(err)); got != 2 {
+		t.Fatalf("expected both errors but got %d", got)
+	}
+	if got := ErrorCode(ErrorItems(err)[1].Err); got != CodeDatabase {
+		t.Errorf("expected the cause to be the first error but got %q", got)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("expected context to be canceled")
+	}
+}
@@ -0,0 +1,264 @@
+// Package grpcmw provides gRPC interceptors which carry package e codes,
+// messages and retryability across service boundaries.
+//
+// Server interceptors convert returned errors into gRPC statuses. Only the
+// client-facing parts of the error are sent: the status message is
+// e.ErrorMessage(err) and the code, id and retryability are attached as an
+// errdetails.ErrorInfo.
+// Client interceptors convert received statuses back into errors which can be
+// introspected with e.ErrorCode, e.ErrorMessage and e.IsRetryable.
+// Recovery interceptors convert panics of handlers into statuses of errors with
+// e.CodePanic.
+package grpcmw
+
+import (
+	"context"
+
+	"github.com/kisunji/e"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain identifies errdetails.ErrorInfo details produced by this package.
+const Domain = "github.com/kisunji/e"
+
+const (
+	metadataRetryable = "retryable"
+	metadataID        = "id"
+)
+
+var toGRPC = map[string]codes.Code{
+	e.CodeNotFound:    codes.NotFound,
+	e.CodeInvalid:     codes.InvalidArgument,
+	e.CodePermission:  codes.PermissionDenied,
+	e.CodeTimeout:     codes.DeadlineExceeded,
+	e.CodeCanceled:    codes.Canceled,
+	e.CodeConflict:    codes.Aborted,
+	e.CodeUnavailable: codes.Unavailable,
+	e.CodeRateLimited: codes.ResourceExhausted,
+	e.CodePanic:       codes.Internal,
+}
+
+var fromGRPC = map[codes.Code]string{
+	codes.NotFound:          e.CodeNotFound,
+	codes.InvalidArgument:   e.CodeInvalid,
+	codes.PermissionDenied:  e.CodePermission,
+	codes.DeadlineExceeded:  e.CodeTimeout,
+	codes.Canceled:          e.CodeCanceled,
+	codes.Aborted:           e.CodeConflict,
+	codes.AlreadyExists:     e.CodeConflict,
+	codes.Unavailable:       e.CodeUnavailable,
+	codes.ResourceExhausted: e.CodeRateLimited,
+}
+
+// ToStatus converts err into a gRPC status with the GRPCCode of the
+// e.ErrorCodeInfo of err, or the gRPC code matching its code. Errors which
+// already are gRPC statuses and carry no code are returned as-is.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	code := e.ErrorCode(err)
+	if st, ok := status.FromError(err); ok && code == "" {
+		return st
+	}
+
+	grpcCode, ok := toGRPC[code]
+	if info, found := e.ErrorCodeInfo(err); found && info.GRPCCode != 0 {
+		grpcCode, ok = codes.Code(info.GRPCCode), true
+	}
+	if !ok {
+		grpcCode = codes.Unknown
+	}
+	st := status.New(grpcCode, e.ErrorMessage(err))
+	if code == "" {
+		return st
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason: code,
+		Domain: Domain,
+	}
+	if e.IsRetryable(err) || e.ErrorID(err) != "" {
+		info.Metadata = make(map[string]string)
+	}
+	if e.IsRetryable(err) {
+		info.Metadata[metadataRetryable] = "true"
+	}
+	if id := e.ErrorID(err); id != "" {
+		info.Metadata[metadataID] = id
+	}
+	if withDetails, err := st.WithDetails(info); err == nil {
+		st = withDetails
+	}
+	return st
+}
+
+// FromStatus converts a gRPC status into an error compatible with package e.
+// The code is taken from an ErrorInfo detail produced by ToStatus, or mapped
+// from the gRPC code onto a canonical code otherwise.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	remote := statusError{
+		code:   fromGRPC[st.Code()],
+		status: st,
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == Domain {
+			remote.code = info.GetReason()
+			remote.retryable = info.GetMetadata()[metadataRetryable] == "true"
+			remote.id = info.GetMetadata()[metadataID]
+			break
+		}
+	}
+	return remote
+}
+
+// UnaryServerInterceptor converts errors returned by unary handlers into
+// gRPC statuses.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor converts errors returned by stream handlers into
+// gRPC statuses.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToStatus(err).Err()
+		}
+		return nil
+	}
+}
+
+// UnaryServerRecoveryInterceptor converts panics of unary handlers into
+// errors with e.FromPanic, named after the full method, reports them with
+// e.Report and returns them as gRPC statuses like UnaryServerInterceptor.
+func UnaryServerRecoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recoverPanic(ctx, info.FullMethod, recovered)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecoveryInterceptor converts panics of stream handlers into
+// errors with e.FromPanic, named after the full method, reports them with
+// e.Report and returns them as gRPC statuses like StreamServerInterceptor.
+func StreamServerRecoveryInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recoverPanic(ss.Context(), info.FullMethod, recovered)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recoverPanic(ctx context.Context, method string, recovered interface{}) error {
+	err := e.FromPanic(method, recovered)
+	e.Report(ctx, err)
+	return ToStatus(err).Err()
+}
+
+// UnaryClientInterceptor converts statuses returned by unary calls into
+// errors compatible with package e.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return fromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor converts statuses returned by streaming calls into
+// errors compatible with package e.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromError(err)
+		}
+		return clientStream{ClientStream: cs}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (cs clientStream) SendMsg(m interface{}) error {
+	return fromError(cs.ClientStream.SendMsg(m))
+}
+
+func (cs clientStream) RecvMsg(m interface{}) error {
+	return fromError(cs.ClientStream.RecvMsg(m))
+}
+
+// fromError converts err if it is a gRPC status. Other errors such as io.EOF
+// are returned unchanged.
+func fromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		return FromStatus(st)
+	}
+	return err
+}
+
+// statusError implements e.ClientFacing, e.Retrier and e.HasID so it can be
+// introspected with e.ErrorCode, e.ErrorMessage, e.IsRetryable and e.ErrorID.
+type statusError struct {
+	code      string
+	id        string
+	retryable bool
+	status    *status.Status
+}
+
+func (s statusError) Error() string {
+	if s.code == "" {
+		return s.status.Err().Error()
+	}
+	return "[" + s.code + "] " + s.status.Err().Error() // localizer.Ignore
+}
+
+func (s statusError) Unwrap() error {
+	return s.status.Err()
+}
+
+// GRPCStatus allows status.FromError and status.Code to be used with
+// converted errors.
+func (s statusError) GRPCStatus() *status.Status {
+	return s.status
+}
+
+func (s statusError) ClientCode() string {
+	return s.code
+}
+
+func (s statusError) ClientMessage() string {
+	return s.status.Message()
+}
+
+func (s statusError) Retryable() bool {
+	return s.retryable
+}
+
+func (s statusError) ID() string {
+	return s.id
+}
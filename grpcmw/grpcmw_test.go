@@ -0,0 +1,129 @@
+package grpcmw
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kisunji/e"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoundTrip(t *testing.T) {
+	serverErr := e.NewError(e.CodeUnavailable, "db is down").
+		SetMessage("Please try again").
+		SetRetryable(true)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, serverErr
+	}
+	_, sent := UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+
+	st, ok := status.FromError(sent)
+	if !ok {
+		t.Fatalf("expected gRPC status but got %v", sent)
+	}
+	if st.Code() != codes.Unavailable {
+		t.Errorf("\ngot:  %v\nwant: %v", st.Code(), codes.Unavailable)
+	}
+	if st.Message() != "Please try again" {
+		t.Errorf("expected only client message to be sent but got %q", st.Message())
+	}
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return sent
+	}
+	received := UnaryClientInterceptor()(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+
+	if got := e.ErrorCode(received); got != e.CodeUnavailable {
+		t.Errorf("\ngot:  %q\nwant: %q", got, e.CodeUnavailable)
+	}
+	if got := e.ErrorMessage(received); got != "Please try again" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "Please try again")
+	}
+	if !e.IsRetryable(received) {
+		t.Errorf("expected received error to be retryable")
+	}
+	if status.Code(received) != codes.Unavailable {
+		t.Errorf("expected received error to remain a gRPC status")
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	t.Run("custom code is carried in details", func(t *testing.T) {
+		st := ToStatus(e.NewError("quota_exceeded", "too many bars"))
+		if st.Code() != codes.Unknown {
+			t.Errorf("\ngot:  %v\nwant: %v", st.Code(), codes.Unknown)
+		}
+		if got := e.ErrorCode(FromStatus(st)); got != "quota_exceeded" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "quota_exceeded")
+		}
+	})
+	t.Run("registered gRPC code", func(t *testing.T) {
+		e.RegisterCode("tenant_suspended", e.CodeInfo{GRPCCode: uint32(codes.FailedPrecondition)})
+		st := ToStatus(e.NewError("tenant_suspended", "tenant 12 is suspended"))
+		if st.Code() != codes.FailedPrecondition {
+			t.Errorf("\ngot:  %v\nwant: %v", st.Code(), codes.FailedPrecondition)
+		}
+	})
+	t.Run("existing status is passed through", func(t *testing.T) {
+		st := ToStatus(status.Error(codes.ResourceExhausted, "slow down"))
+		if st.Code() != codes.ResourceExhausted {
+			t.Errorf("\ngot:  %v\nwant: %v", st.Code(), codes.ResourceExhausted)
+		}
+	})
+	t.Run("internal details are not sent", func(t *testing.T) {
+		st := ToStatus(errors.New("secret connection string"))
+		if st.Message() != "" {
+			t.Errorf("expected blank message but got %q", st.Message())
+		}
+	})
+}
+
+func TestFromStatus(t *testing.T) {
+	t.Run("foreign status is mapped to canonical code", func(t *testing.T) {
+		err := FromStatus(status.New(codes.NotFound, "no bar"))
+		if got := e.ErrorCode(err); got != e.CodeNotFound {
+			t.Errorf("\ngot:  %q\nwant: %q", got, e.CodeNotFound)
+		}
+	})
+	t.Run("ok status returns nil", func(t *testing.T) {
+		if err := FromStatus(status.New(codes.OK, "")); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+}
+
+func TestRecoveryInterceptors(t *testing.T) {
+	unary := func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("bar is nil")
+	}
+	_, err := UnaryServerRecoveryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/bar.v1.BarService/GetBar"}, unary)
+	stream := func(srv interface{}, ss grpc.ServerStream) error {
+		panic("bar is nil")
+	}
+	streamErr := StreamServerRecoveryInterceptor()(nil, serverStream{}, &grpc.StreamServerInfo{FullMethod: "/bar.v1.BarService/ListBars"}, stream)
+
+	for _, err := range []error{err, streamErr} {
+		st, ok := status.FromError(err)
+		if !ok {
+			t.Fatalf("expected gRPC status but got %v", err)
+		}
+		if st.Code() != codes.Internal {
+			t.Errorf("\ngot:  %v\nwant: %v", st.Code(), codes.Internal)
+		}
+		if got := e.ErrorCode(FromStatus(st)); got != e.CodePanic {
+			t.Errorf("\ngot:  %q\nwant: %q", got, e.CodePanic)
+		}
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+}
+
+func (serverStream) Context() context.Context {
+	return context.Background()
+}
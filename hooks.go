@@ -0,0 +1,92 @@
+package e
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// hooks are run by NewError, NewErrorf, Wrap and Wrapf.
+	hooks hookList
+	// newHooks are only run by NewError and NewErrorf, after hooks.
+	newHooks hookList
+)
+
+// AddHook registers fn to be called with every Error constructed by NewError,
+// NewErrorf, Wrap and Wrapf. The Error returned by fn replaces the constructed
+// Error, which allows hooks to decorate it (e.g. with SetRetryable). If fn
+// returns nil, the Error is left unchanged.
+//
+// Hooks are called synchronously in the order they were registered and should
+// be cheap. AddHook is safe to call concurrently but is intended to be called
+// during initialization.
+//
+// Usage:
+//
+//	func init() {
+//		e.AddHook(func(err e.Error) e.Error {
+//			if e.ErrorCode(err) == e.CodeUnavailable {
+//				return err.SetRetryable(true)
+//			}
+//			return err
+//		})
+//	}
+func AddHook(fn func(Error) Error) {
+	hooks.add(fn)
+}
+
+// OnNew registers fn to be called with every Error created by NewError and
+// NewErrorf. Hooks are called synchronously in the order they were registered
+// and should be cheap. OnNew is safe to call concurrently but is intended to be
+// called during initialization.
+//
+// Usage:
+//
+//	func init() {
+//		e.OnNew(func(err e.Error) {
+//			errorsCreated.WithLabelValues(e.ErrorCode(err)).Inc()
+//		})
+//	}
+func OnNew(fn func(Error)) {
+	newHooks.add(func(err Error) Error {
+		fn(err)
+		return err
+	})
+}
+
+type hookList struct {
+	mu  sync.Mutex
+	fns atomic.Value // []func(Error) Error
+}
+
+func (l *hookList) add(fn func(Error) Error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fns, _ := l.fns.Load().([]func(Error) Error)
+	// copy so that concurrent readers never observe a partially updated slice
+	updated := make([]func(Error) Error, len(fns), len(fns)+1)
+	copy(updated, fns)
+	l.fns.Store(append(updated, fn))
+}
+
+func (l *hookList) run(err Error) Error {
+	fns, _ := l.fns.Load().([]func(Error) Error)
+	for _, fn := range fns {
+		// a hook must not turn a non-nil error into nil
+		if decorated := fn(err); decorated != nil {
+			err = decorated
+		}
+	}
+	return err
+}
+
+func (l *hookList) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fns.Store(([]func(Error) Error)(nil))
+}
+
+func runNewHooks(err Error) Error {
+	return newHooks.run(hooks.run(err))
+}
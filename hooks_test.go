@@ -0,0 +1,66 @@
+package e
+
+import "testing"
+
+func TestOnNew(t *testing.T) {
+	t.Cleanup(newHooks.reset)
+
+	var codes []string
+	OnNew(func(err Error) {
+		codes = append(codes, ErrorCode(err))
+	})
+
+	_ = NewError(CodeNotFound, "cannot find bar")
+	_ = NewErrorf(CodeTimeout, "timed out after %ds", 3)
+	_ = Wrap(Foo())
+
+	want := []string{CodeNotFound, CodeTimeout, CodeDatabase}
+	if len(codes) != len(want) {
+		t.Fatalf("\ngot:  %q\nwant: %q", codes, want)
+	}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Errorf("\ngot:  %q\nwant: %q", codes, want)
+		}
+	}
+}
+
+func TestAddHook(t *testing.T) {
+	t.Cleanup(hooks.reset)
+
+	var calls int
+	AddHook(func(err Error) Error {
+		calls++
+		return err.SetRetryable(true)
+	})
+
+	err := Wrapf(Wrap(Foo()), "id: %d", 1)
+	if calls != 3 {
+		t.Errorf("expected hook to run for NewError, Wrap and Wrapf but ran %d times", calls)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("expected hook to decorate error")
+	}
+}
+
+func TestAddHookReturningNil(t *testing.T) {
+	t.Cleanup(hooks.reset)
+
+	AddHook(func(err Error) Error { return nil })
+	var calls int
+	AddHook(func(err Error) Error {
+		calls++
+		return err
+	})
+
+	err := Wrap(Foo())
+	if err == nil {
+		t.Fatalf("expected hook returning nil to leave the error unchanged")
+	}
+	if calls != 2 {
+		t.Errorf("expected later hooks to still run but ran %d times", calls)
+	}
+	if got, want := err.Error(), "TestAddHookReturningNil: Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
@@ -0,0 +1,283 @@
+package e
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statusClientClosedRequest is the non-standard status code used by nginx
+// when a client closes the connection before the response is sent.
+const statusClientClosedRequest = 499
+
+// maxHTTPBodySize limits how much of a response body FromHTTPResponse reads.
+const maxHTTPBodySize = 1 << 20
+
+// Headers used by EncodeHeaders and DecodeHeaders to carry an error.
+const (
+	HeaderCode      = "X-Err-Code"
+	HeaderMessage   = "X-Err-Message"
+	HeaderID        = "X-Err-Id"
+	HeaderRetryable = "X-Err-Retryable"
+)
+
+// httpBody is the JSON representation of an error written by WriteHTTP and
+// read by FromHTTPResponse.
+type httpBody struct {
+	Code    string   `json:"code,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Hint    string   `json:"hint,omitempty"`
+	DocURL  string   `json:"docUrl,omitempty"`
+	ID      string   `json:"id,omitempty"`
+	Source  string   `json:"source,omitempty"`
+	Ops     []string `json:"ops,omitempty"`
+
+	// Fields of a ValidationError.
+	Fields FieldErrors `json:"fields,omitempty"`
+}
+
+var codeToHTTPStatus = map[string]int{
+	CodeNotFound:    http.StatusNotFound,
+	CodeInvalid:     http.StatusBadRequest,
+	CodeValidation:  http.StatusBadRequest,
+	CodePermission:  http.StatusForbidden,
+	CodeTimeout:     http.StatusGatewayTimeout,
+	CodeCanceled:    statusClientClosedRequest,
+	CodeConflict:    http.StatusConflict,
+	CodeUnavailable: http.StatusServiceUnavailable,
+	CodeRateLimited: http.StatusTooManyRequests,
+}
+
+var httpStatusToCode = map[int]string{
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusBadRequest:          CodeInvalid,
+	http.StatusUnprocessableEntity: CodeInvalid,
+	http.StatusUnauthorized:        CodePermission,
+	http.StatusForbidden:           CodePermission,
+	http.StatusRequestTimeout:      CodeTimeout,
+	http.StatusGatewayTimeout:      CodeTimeout,
+	statusClientClosedRequest:      CodeCanceled,
+	http.StatusConflict:            CodeConflict,
+	http.StatusBadGateway:          CodeUnavailable,
+	http.StatusServiceUnavailable:  CodeUnavailable,
+	http.StatusTooManyRequests:     CodeRateLimited,
+}
+
+// HTTPStatus returns the status of the ErrorCodeInfo of err, or the HTTP
+// status code matching the first code of err. Errors with unknown codes get
+// the default status of their first kind, e.g. http.StatusBadRequest for
+// KindClient. Returns http.StatusInternalServerError otherwise.
+func HTTPStatus(err error) int {
+	if info, ok := ErrorCodeInfo(err); ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+	if status, ok := codeToHTTPStatus[ErrorCode(err)]; ok {
+		return status
+	}
+	if status, ok := kindToHTTPStatus[ErrorKind(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteHTTP writes err to w as a JSON body containing the code, message, hint,
+// documentation URL, id, source and ops of the error chain, using
+// HTTPStatus(err) as the status code. Field errors of a ValidationError are
+// written as a fields array. A Retry-After header is set if the error chain
+// has a retry-after duration. Ops are left out in public mode so that internal
+// function names do not reach clients.
+//
+// Usage:
+//
+//	func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//		if err := doSomething(r); err != nil {
+//			e.WriteHTTP(w, err)
+//			return
+//		}
+//	}
+func WriteHTTP(w http.ResponseWriter, err error) {
+	writeHTTP(w, err, ErrorMessage(err))
+}
+
+// WriteHTTPLocalized behaves like WriteHTTP but writes the message translated
+// by LocalizedMessage according to the Accept-Language header of r, setting a
+// Content-Language header if a translation was used.
+//
+// Usage:
+//
+//	func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//		if err := doSomething(r); err != nil {
+//			e.WriteHTTPLocalized(w, r, err)
+//			return
+//		}
+//	}
+func WriteHTTPLocalized(w http.ResponseWriter, r *http.Request, err error) {
+	msg, lang := LocalizedMessage(err, r.Header.Get("Accept-Language"))
+	w.Header().Add("Vary", "Accept-Language")
+	if lang != "" {
+		w.Header().Set("Content-Language", lang)
+	}
+	writeHTTP(w, err, msg)
+}
+
+func writeHTTP(w http.ResponseWriter, err error, msg string) {
+	body := httpBody{
+		Code:    ErrorCode(err),
+		Message: msg,
+		Hint:    ErrorHint(err),
+		DocURL:  ErrorDocURL(err),
+		ID:      ErrorID(err),
+		Source:  Source(err),
+		Fields:  ValidationErrors(err),
+	}
+	if !publicMode.Load() {
+		body.Ops = Ops(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if d, ok := ErrorRetryAfter(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+	w.WriteHeader(HTTPStatus(err))
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// EncodeHeaders sets the code, message, id and retryability of err as headers
+// of h, for responses which cannot carry the JSON body written by WriteHTTP,
+// e.g. because the body is streamed. Messages which are not printable ASCII
+// are encoded as RFC 2047 encoded-words. A Retry-After header is set if the
+// error chain has a retry-after duration. Does nothing if err is nil.
+//
+// Usage:
+//
+//	e.EncodeHeaders(w.Header(), err)
+//	w.WriteHeader(e.HTTPStatus(err))
+//	_, _ = io.Copy(w, partial)
+func EncodeHeaders(h http.Header, err error) {
+	if err == nil {
+		return
+	}
+	if code := ErrorCode(err); code != "" {
+		h.Set(HeaderCode, code)
+	}
+	if msg := ErrorMessage(err); msg != "" {
+		h.Set(HeaderMessage, mime.QEncoding.Encode("utf-8", msg))
+	}
+	if id := ErrorID(err); id != "" {
+		h.Set(HeaderID, id)
+	}
+	if IsRetryable(err) {
+		h.Set(HeaderRetryable, "true")
+	}
+	if d, ok := ErrorRetryAfter(err); ok {
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+}
+
+// DecodeHeaders reconstructs an Error from headers set by EncodeHeaders.
+// Returns nil if h carries neither a code nor an id.
+func DecodeHeaders(h http.Header) Error {
+	code, id := h.Get(HeaderCode), h.Get(HeaderID)
+	if code == "" && id == "" {
+		return nil
+	}
+
+	msg, decodeErr := new(mime.WordDecoder).DecodeHeader(h.Get(HeaderMessage))
+	if decodeErr != nil {
+		msg = h.Get(HeaderMessage)
+	}
+	decoded := errorImpl{
+		code:      code,
+		message:   msg,
+		id:        id,
+		retryable: h.Get(HeaderRetryable) == "true",
+		err:       errors.New("error from response headers"),
+		stack:     callers(1),
+	}
+	if d := parseRetryAfter(h.Get("Retry-After")); d > 0 {
+		decoded.retryAfter = d
+		decoded.retryable = true
+	}
+	return decoded
+}
+
+// FromHTTPResponse reconstructs an Error from a response written by WriteHTTP.
+// Returns nil if resp does not have an error status code.
+//
+// If the body cannot be parsed, the code is derived from the status code.
+// A Retry-After header is available with ErrorRetryAfter.
+// The body is read but not closed.
+//
+// Usage:
+//
+//	resp, err := http.Get(url)
+//	if err != nil {
+//		return e.Wrap(err)
+//	}
+//	defer resp.Body.Close()
+//	if err := e.FromHTTPResponse(resp); err != nil {
+//		return e.Wrap(err)
+//	}
+func FromHTTPResponse(resp *http.Response) Error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	var body httpBody
+	if resp.Body != nil {
+		_ = json.NewDecoder(io.LimitReader(resp.Body, maxHTTPBodySize)).Decode(&body)
+	}
+	if body.Code == "" {
+		body.Code = httpStatusToCode[resp.StatusCode]
+	}
+
+	var cause error = errors.New(resp.Status)
+	if len(body.Fields) > 0 {
+		cause = &body.Fields
+	}
+
+	// The innermost op holds the code and message like the original error.
+	rebuilt := errorImpl{
+		code:    body.Code,
+		message: body.Message,
+		hint:    body.Hint,
+		id:      body.ID,
+		source:  body.Source,
+		err:     cause,
+		stack:   callers(1),
+	}
+	if d := parseRetryAfter(resp.Header.Get("Retry-After")); d > 0 {
+		rebuilt.retryAfter = d
+		rebuilt.retryable = true
+	}
+	if n := len(body.Ops); n > 0 {
+		rebuilt.op = body.Ops[n-1]
+	}
+	for i := len(body.Ops) - 2; i >= 0; i-- {
+		rebuilt = errorImpl{
+			op:    body.Ops[i],
+			err:   rebuilt,
+			stack: rebuilt.stack,
+		}
+	}
+	return rebuilt
+}
+
+// parseRetryAfter parses the value of a Retry-After header given either in
+// seconds or as an HTTP date. Returns 0 if value cannot be parsed.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
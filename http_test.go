@@ -0,0 +1,145 @@
+package e
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromHTTPResponse(t *testing.T) {
+	t.Run("round trips WriteHTTP", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, Wrap(Foo()).SetMessage("try again"))
+
+		err := FromHTTPResponse(rec.Result())
+		if err == nil {
+			t.Fatalf("expected error but got nil")
+		}
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("\ngot:  %d\nwant: %d", rec.Code, http.StatusInternalServerError)
+		}
+		if got := ErrorCode(err); got != CodeDatabase {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeDatabase)
+		}
+		if got := ErrorMessage(err); got != "try again" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "try again")
+		}
+		if got, want := Ops(err), []string{"TestFromHTTPResponse.func1", "Foo"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		want := "TestFromHTTPResponse.func1: Foo: [database_error] 500 Internal Server Error"
+		if err.Error() != want {
+			t.Errorf("\ngot:  %q\nwant: %q", err, want)
+		}
+	})
+	t.Run("public mode omits ops", func(t *testing.T) {
+		SetPublicMode(true)
+		t.Cleanup(func() { SetPublicMode(false) })
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, Wrap(Foo()).SetMessage("try again"))
+		if body := rec.Body.String(); strings.Contains(body, "ops") {
+			t.Errorf("expected no ops but got %s", body)
+		}
+	})
+	t.Run("non-JSON body derives code from status", func(t *testing.T) {
+		resp := &http.Response{
+			Status:     "404 Not Found",
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader("<html>not found</html>")),
+		}
+		if got := ErrorCode(FromHTTPResponse(resp)); got != CodeNotFound {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeNotFound)
+		}
+	})
+	t.Run("success status returns nil", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusOK}
+		if err := FromHTTPResponse(resp); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := Wrap(NewError(CodeRateLimited, "tenant 12 over limit").SetRetryAfter(1500 * time.Millisecond))
+	if d, ok := ErrorRetryAfter(err); !ok || d != 1500*time.Millisecond {
+		t.Errorf("unexpected retry after %v, %v", d, ok)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("expected SetRetryAfter to mark error as retryable")
+	}
+	if _, ok := ErrorRetryAfter(Foo()); ok {
+		t.Errorf("expected no retry after")
+	}
+
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, err)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("\ngot:  %d\nwant: %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "2")
+	}
+
+	received := FromHTTPResponse(rec.Result())
+	if d, ok := ErrorRetryAfter(received); !ok || d != 2*time.Second {
+		t.Errorf("unexpected received retry after %v, %v", d, ok)
+	}
+	if got := ErrorCode(received); got != CodeRateLimited {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeRateLimited)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	err := Wrap(NewError(CodeRateLimited, "tenant 12 over limit")).
+		SetMessage("Zu viele Anfragen").
+		SetRetryAfter(2 * time.Second)
+	h := http.Header{}
+	EncodeHeaders(h, err)
+
+	if got := h.Get(HeaderCode); got != CodeRateLimited {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeRateLimited)
+	}
+	if got := h.Get("Retry-After"); got != "2" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "2")
+	}
+
+	decoded := DecodeHeaders(h)
+	if decoded == nil {
+		t.Fatalf("expected error but got nil")
+	}
+	if got := ErrorCode(decoded); got != CodeRateLimited {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeRateLimited)
+	}
+	if got := ErrorMessage(decoded); got != "Zu viele Anfragen" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "Zu viele Anfragen")
+	}
+	if got, want := ErrorID(decoded), ErrorID(err); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if !IsRetryable(decoded) {
+		t.Errorf("expected decoded error to be retryable")
+	}
+	if d, ok := ErrorRetryAfter(decoded); !ok || d != 2*time.Second {
+		t.Errorf("unexpected retry after %v, %v", d, ok)
+	}
+
+	t.Run("non-ASCII message", func(t *testing.T) {
+		h := http.Header{}
+		EncodeHeaders(h, NewError(CodeNotFound, "cannot find bar").SetMessage("Bär nicht gefunden"))
+		if got := h.Get(HeaderMessage); got == "Bär nicht gefunden" {
+			t.Errorf("expected message to be encoded but got %q", got)
+		}
+		if got := ErrorMessage(DecodeHeaders(h)); got != "Bär nicht gefunden" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "Bär nicht gefunden")
+		}
+	})
+	t.Run("no headers returns nil", func(t *testing.T) {
+		if err := DecodeHeaders(http.Header{}); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package e
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var idGenerator atomic.Value // func() string
+
+// SetIDGenerator enables automatic ids: fn is called by NewError, NewErrorf and
+// by Wrap when the wrapped error does not have an id yet. Use NewULID for
+// lexicographically sortable ids. Passing nil disables automatic ids.
+//
+// Usage:
+//
+//	func init() {
+//		e.SetIDGenerator(e.NewULID)
+//	}
+func SetIDGenerator(fn func() string) {
+	idGenerator.Store(fn)
+}
+
+// SetTestIDGenerator enables automatic ids which are deterministic for tests:
+// prefix followed by a counter starting at 1, e.g. "err-1", "err-2". Every call
+// restarts the counter. See SetTestClock for an example.
+func SetTestIDGenerator(prefix string) {
+	var n atomic.Int64
+	SetIDGenerator(func() string {
+		return prefix + strconv.FormatInt(n.Add(1), 10)
+	})
+}
+
+func generateID() string {
+	if fn, _ := idGenerator.Load().(func() string); fn != nil {
+		return fn()
+	}
+	return ""
+}
+
+// crockford is the base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a new ULID (https://github.com/ulid/spec): a 26 character
+// id made of a millisecond timestamp followed by 80 random bits.
+func NewULID() string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	_, _ = rand.Read(b[6:])
+
+	// encode 128 bits as 26 base32 characters, most significant first
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
@@ -0,0 +1,54 @@
+package e
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorID(t *testing.T) {
+	t.Run("ids are not generated by default", func(t *testing.T) {
+		if got := ErrorID(Foo()); got != "" {
+			t.Errorf("expected blank but got %q", got)
+		}
+	})
+	t.Run("SetID is returned", func(t *testing.T) {
+		err := Wrap(NewError(CodeDatabase, "cannot foo").SetID("abc123"))
+		if got := ErrorID(err); got != "abc123" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "abc123")
+		}
+	})
+	t.Run("generated id is kept through wraps", func(t *testing.T) {
+		SetIDGenerator(NewULID)
+		t.Cleanup(func() { SetIDGenerator(nil) })
+
+		inner := Wrap(errors.New("basic error"))
+		id := ErrorID(inner)
+		if len(id) != 26 {
+			t.Fatalf("expected ULID but got %q", id)
+		}
+		if got := ErrorID(Wrap(Wrap(inner))); got != id {
+			t.Errorf("\ngot:  %q\nwant: %q", got, id)
+		}
+	})
+	t.Run("id is included in client output", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, NewError(CodeDatabase, "cannot foo").SetID("abc123"))
+		if got := ErrorID(FromHTTPResponse(rec.Result())); got != "abc123" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "abc123")
+		}
+	})
+}
+
+func TestNewULID(t *testing.T) {
+	a, b := NewULID(), NewULID()
+	if a == b {
+		t.Errorf("expected unique ids but got %q twice", a)
+	}
+	for _, c := range a {
+		if !strings.ContainsRune(crockford, c) {
+			t.Fatalf("unexpected character %q in %q", c, a)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package e
+
+import (
+	"context"
+	"strings"
+)
+
+// FieldIgnored is the field which marks errors passed to Ignore.
+const FieldIgnored = "ignored"
+
+// Ignore deliberately drops err while keeping it observable: err is wrapped
+// like Wrap with the reasons as info and the FieldIgnored field, passed to the
+// hooks registered with AddHook and then to Report with SeverityInfo, so that
+// only reporters registered for SeverityInfo receive it. Does nothing if err
+// is nil.
+//
+// Usage:
+//
+//	defer func() {
+//		e.Ignore(f.Close(), "file was only read")
+//	}()
+func Ignore(err error, reasons ...string) {
+	if err == nil {
+		return
+	}
+	ignored := wrap(err, strings.Join(reasons, ", "), func(wrapped errorImpl) Error {
+		return wrapped.SetField(FieldIgnored, true)
+	})
+	Report(context.Background(), Attach(ignored, CodeInfo{Severity: SeverityInfo}))
+}
+
+// IsIgnored returns true if err was passed to Ignore, e.g. for hooks and
+// reporters which treat ignored errors differently.
+func IsIgnored(err error) bool {
+	ignored, _ := ErrorFields(err)[FieldIgnored].(bool)
+	return ignored
+}
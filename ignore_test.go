@@ -0,0 +1,48 @@
+package e
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIgnore(t *testing.T) {
+	t.Cleanup(resetReporters)
+	t.Cleanup(hooks.reset)
+
+	var hooked int
+	AddHook(func(err Error) Error {
+		if IsIgnored(err) {
+			hooked++
+		}
+		return err
+	})
+	var infos, pages []error
+	RegisterReporter(ReporterFunc(func(_ context.Context, err error, _ Severity) {
+		infos = append(infos, err)
+	}), SeverityInfo)
+	RegisterReporter(ReporterFunc(func(_ context.Context, err error, _ Severity) {
+		pages = append(pages, err)
+	}), SeverityError)
+
+	Ignore(nil)
+	Ignore(Foo(), "cleanup is best effort")
+
+	if hooked != 1 {
+		t.Errorf("expected hooks to see 1 ignored error but got %d", hooked)
+	}
+	if len(pages) != 0 {
+		t.Errorf("expected ignored errors to not be paged but got %v", pages)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 reported error but got %v", infos)
+	}
+	if want := "TestIgnore: (cleanup is best effort): Foo: [database_error] cannot foo"; infos[0].Error() != want {
+		t.Errorf("\ngot:  %q\nwant: %q", infos[0], want)
+	}
+	if !IsIgnored(infos[0]) {
+		t.Errorf("expected reported error to be marked as ignored")
+	}
+	if IsIgnored(Foo()) {
+		t.Errorf("expected other errors to not be marked as ignored")
+	}
+}
@@ -1,6 +1,12 @@
 package e
 
-import "errors"
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
 
 // The following interfaces can be easily implemented by existing custom error types
 // to maintain compatibility with package e.
@@ -22,30 +28,172 @@ type ClientFacing interface {
 	ClientMessage() string
 }
 
+// HasCode allows custom error types which cannot implement ClientFacing, such
+// as those of libraries which do not import package e, to be used with utility
+// functions ErrorCode() and Codes().
+type HasCode interface {
+
+	// ErrCode returns a short string representing the type of error, such as
+	// "database_error".
+	ErrCode() string
+}
+
+// HasMessage allows custom error types which cannot implement ClientFacing to
+// be used with utility functions ErrorMessage() and Messages().
+type HasMessage interface {
+
+	// ClientMessage returns a user-friendly error message (if any) which is logically
+	// separate from the error cause.
+	ClientMessage() string
+}
+
+// codeOf returns the canonical code of err if it implements ClientFacing or
+// HasCode.
+func codeOf(err error) string {
+	if e, ok := err.(ClientFacing); ok && e.ClientCode() != "" {
+		return canonicalCode(e.ClientCode())
+	}
+	if e, ok := err.(HasCode); ok {
+		return canonicalCode(e.ErrCode())
+	}
+	return ""
+}
+
+// messageOf returns the message of err if it implements ClientFacing or
+// HasMessage.
+func messageOf(err error) string {
+	if e, ok := err.(HasMessage); ok {
+		return e.ClientMessage()
+	}
+	return ""
+}
+
 // ErrorCode returns the first unwrapped Code of an error which implements
-// ClientFacing interface. Otherwise returns an empty string.
+// ClientFacing or HasCode interface. Otherwise returns an empty string. Codes
+// deprecated with AliasCode are replaced by the codes they are aliases of.
 func ErrorCode(err error) string {
-	for err != nil {
-		if e, ok := err.(ClientFacing); ok && e.ClientCode() != "" {
-			return e.ClientCode()
+	for err := range chain(err) {
+		if code := codeOf(err); code != "" {
+			return code
 		}
-		err = errors.Unwrap(err)
 	}
 	return ""
 }
 
+// Codes returns the code of every error in the chain which implements
+// ClientFacing or HasCode interface, ordered from outermost to innermost, e.g.
+// both the "internal_error" reported by a handler and the "database_error" at
+// the root. Use slices.Compact to drop repeated codes of adjacent errors.
+func Codes(err error) []string {
+	var codes []string
+	for err := range chain(err) {
+		if code := codeOf(err); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+var mergeMessages atomic.Bool
+
+// SetMessageMerging makes ErrorMessage join the messages of every error in the
+// chain, outermost first, instead of returning only the first one, e.g.
+// "Payment failed: Card declined". This keeps essential details of inner
+// errors from being hidden by generic outer messages wherever ErrorMessage is
+// used, such as in WriteHTTP. Repeated messages of adjacent errors are
+// merged once.
+func SetMessageMerging(enabled bool) {
+	mergeMessages.Store(enabled)
+}
+
+var messagePolicy atomic.Pointer[func(code, msg string) string]
+
+// SetMessagePolicy registers policy to suppress or rewrite every message
+// returned by ErrorMessage, e.g. to never show raw messages of errors with
+// CodeInternal, without touching call sites. policy is called with the first
+// code and the message of an error, which may be empty so that a default
+// message can be supplied. A nil policy removes the current one.
+//
+// Usage:
+//
+//	e.SetMessagePolicy(func(code, msg string) string {
+//		if code == CodeInternal {
+//			return "Something went wrong"
+//		}
+//		return msg
+//	})
+func SetMessagePolicy(policy func(code, msg string) string) {
+	if policy == nil {
+		messagePolicy.Store(nil)
+		return
+	}
+	messagePolicy.Store(&policy)
+}
+
 // ErrorMessage returns the first unwrapped Message of an error which implements
-// ClientFacing interface. Otherwise returns an empty string.
+// ClientFacing or HasMessage interface, or all of them if enabled with
+// SetMessageMerging. Otherwise returns an empty string. Messages hidden with
+// ClearMessages are skipped, as are messages of errors whose own code was
+// marked with MarkSensitive, or all of them if ErrorCode of err was. The
+// DefaultMessage of the ErrorCodeInfo of err is returned if there is no
+// message. The result is passed through the policy set with SetMessagePolicy.
 func ErrorMessage(err error) string {
-	for err != nil {
-		if e, ok := err.(ClientFacing); ok && e.ClientMessage() != "" {
-			return e.ClientMessage()
+	if err == nil {
+		return ""
+	}
+	code := ErrorCode(err)
+	var msg string
+	if !isSensitive(code) {
+		msg = errorMessage(err)
+	}
+	if msg == "" {
+		if info, ok := ErrorCodeInfo(err); ok {
+			msg = info.DefaultMessage
+		}
+	}
+	if policy := messagePolicy.Load(); policy != nil {
+		return (*policy)(code, msg)
+	}
+	return msg
+}
+
+// errorMessage implements ErrorMessage before the message policy is applied.
+func errorMessage(err error) string {
+	if mergeMessages.Load() {
+		return strings.Join(slices.Compact(Messages(err)), ": ")
+	}
+	for err := range chain(err) {
+		if msg := publicMessageOf(err); msg != "" {
+			return msg
+		}
+		if hidesMessages(err) {
+			break
 		}
-		err = errors.Unwrap(err)
 	}
 	return ""
 }
 
+// Messages returns the message of every error in the chain which implements
+// ClientFacing or HasMessage interface, ordered from outermost to innermost.
+// Messages hidden with ClearMessages are skipped, as are messages of errors
+// whose own code was marked with MarkSensitive, or all of them if ErrorCode
+// of err was.
+func Messages(err error) []string {
+	if isSensitive(ErrorCode(err)) {
+		return nil
+	}
+	var msgs []string
+	for err := range chain(err) {
+		if msg := publicMessageOf(err); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		if hidesMessages(err) {
+			break
+		}
+	}
+	return msgs
+}
+
 // HasStacktrace allows custom error types to be used with utility function
 // ErrorStacktrace().
 type HasStacktrace interface {
@@ -58,11 +206,208 @@ type HasStacktrace interface {
 // HasStacktrace interface. Otherwise returns an empty string.
 func ErrorStacktrace(err error) string {
 	var stack string
-	for err != nil {
+	for err := range chain(err) {
 		if e, ok := err.(HasStacktrace); ok && e.Stacktrace() != "" {
 			stack = e.Stacktrace()
 		}
-		err = errors.Unwrap(err)
 	}
 	return stack
 }
+
+// Retrier allows custom error types to be used with utility function
+// IsRetryable().
+type Retrier interface {
+
+	// Retryable reports whether the operation which caused the error can be retried.
+	Retryable() bool
+}
+
+// IsRetryable returns the retryability set with SetRetryable or SetRetryAfter
+// by the outermost error in the chain which set one, so that a single error
+// can opt out of the retryability registered for its code. Otherwise returns
+// true if any error in the chain implements Retrier interface and reports
+// itself as retryable, if the ErrorCodeInfo of err is retryable, or if the
+// first kind of err is KindTransient, and false otherwise.
+func IsRetryable(err error) bool {
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok && impl.retryableSet {
+			return impl.retryable
+		}
+		if e, ok := err.(Retrier); ok && e.Retryable() {
+			return true
+		}
+	}
+	if info, ok := ErrorCodeInfo(err); ok && info.Retryable {
+		return true
+	}
+	return ErrorKind(err) == KindTransient
+}
+
+// HasFields allows custom error types to be used with utility function
+// ErrorFields().
+type HasFields interface {
+
+	// Fields returns structured key-value pairs describing the error, if any.
+	//
+	// Note: ErrorFields() should be used to retrieve the fields of the whole chain.
+	Fields() map[string]interface{}
+}
+
+// ErrorFields returns the fields of every error in the chain which implements
+// HasFields interface, merged into a new map. Fields of outer errors override
+// fields of inner errors with the same key, so a wrap site can refine the
+// fields it inherits without changing the error it wraps. Use ErrorFieldsAt
+// to retrieve the fields of a single error. Returns nil if there are no fields.
+func ErrorFields(err error) map[string]interface{} {
+	var layers []map[string]interface{}
+	for err := range chain(err) {
+		if e, ok := err.(HasFields); ok && len(e.Fields()) > 0 {
+			layers = append(layers, e.Fields())
+		}
+	}
+	if len(layers) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]interface{})
+	for i := len(layers) - 1; i >= 0; i-- {
+		for k, v := range layers[i] {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// ErrorFieldsAt returns a copy of the fields of the error depth errors down the
+// chain of err, where 0 is err itself, without the fields it inherits from the
+// errors it wraps. Returns nil if that error does not exist or has no fields.
+//
+// Usage:
+//
+//	err := e.Wrap(e.NewError(CodeNotFound, "cannot find bar").SetField("id", 1)).SetField("id", 2)
+//	e.ErrorFields(err)      // map[id:2]
+//	e.ErrorFieldsAt(err, 1) // map[id:1]
+func ErrorFieldsAt(err error, depth int) map[string]interface{} {
+	if depth < 0 {
+		return nil
+	}
+	for err := range chain(err) {
+		if depth--; depth >= 0 {
+			continue
+		}
+		if e, ok := err.(HasFields); ok && len(e.Fields()) > 0 {
+			return maps.Clone(e.Fields())
+		}
+		return nil
+	}
+	return nil
+}
+
+// HasID allows custom error types to be used with utility function ErrorID().
+type HasID interface {
+
+	// ID returns the unique id of the error occurrence, if any.
+	ID() string
+}
+
+// ErrorID returns the first unwrapped id of an error which implements HasID
+// interface. Otherwise returns an empty string.
+func ErrorID(err error) string {
+	for err := range chain(err) {
+		if e, ok := err.(HasID); ok && e.ID() != "" {
+			return e.ID()
+		}
+	}
+	return ""
+}
+
+// HasKind allows custom error types to be used with utility function
+// ErrorKind().
+type HasKind interface {
+
+	// Kind returns the category of the error, if any.
+	Kind() Kind
+}
+
+// ErrorKind returns the first unwrapped Kind of an error which implements
+// HasKind interface. Otherwise returns an empty Kind.
+func ErrorKind(err error) Kind {
+	for err := range chain(err) {
+		if e, ok := err.(HasKind); ok && e.Kind() != "" {
+			return e.Kind()
+		}
+	}
+	return ""
+}
+
+// HasHint allows custom error types to be used with utility function
+// ErrorHint().
+type HasHint interface {
+
+	// Hint returns actionable remediation for the user, if any.
+	Hint() string
+}
+
+// ErrorHint returns the first unwrapped Hint of an error which implements
+// HasHint interface. Otherwise returns an empty string.
+func ErrorHint(err error) string {
+	for err := range chain(err) {
+		if e, ok := err.(HasHint); ok && e.Hint() != "" {
+			return e.Hint()
+		}
+	}
+	return ""
+}
+
+// HasTimestamp allows custom error types to be used with utility functions
+// ErrorTimestamp() and ErrorRootTimestamp().
+type HasTimestamp interface {
+
+	// Timestamp returns the time the error was created, or the zero time if it
+	// was not recorded.
+	Timestamp() time.Time
+}
+
+// ErrorTimestamp returns the first unwrapped non-zero timestamp of an error
+// which implements HasTimestamp interface, i.e. the time the error was last
+// wrapped. Otherwise returns the zero time.
+func ErrorTimestamp(err error) time.Time {
+	for err := range chain(err) {
+		if e, ok := err.(HasTimestamp); ok && !e.Timestamp().IsZero() {
+			return e.Timestamp()
+		}
+	}
+	return time.Time{}
+}
+
+// ErrorRootTimestamp returns the last unwrapped non-zero timestamp of an error
+// which implements HasTimestamp interface, i.e. the time the error was first
+// created. Otherwise returns the zero time.
+func ErrorRootTimestamp(err error) time.Time {
+	var ts time.Time
+	for err := range chain(err) {
+		if e, ok := err.(HasTimestamp); ok && !e.Timestamp().IsZero() {
+			ts = e.Timestamp()
+		}
+	}
+	return ts
+}
+
+// HasRetryAfter allows custom error types to be used with utility function
+// ErrorRetryAfter().
+type HasRetryAfter interface {
+
+	// RetryAfter returns how long to wait before retrying, or 0 if unknown.
+	RetryAfter() time.Duration
+}
+
+// ErrorRetryAfter returns the first unwrapped non-zero retry-after duration of
+// an error which implements HasRetryAfter interface. Otherwise returns false.
+func ErrorRetryAfter(err error) (time.Duration, bool) {
+	for err := range chain(err) {
+		if e, ok := err.(HasRetryAfter); ok && e.RetryAfter() > 0 {
+			return e.RetryAfter(), true
+		}
+	}
+	return 0, false
+}
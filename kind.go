@@ -0,0 +1,48 @@
+package e
+
+import "net/http"
+
+// Kind is a broad, machine-readable category of an error such as KindClient,
+// orthogonal to its code. Codes identify what went wrong; kinds describe who
+// is at fault and whether trying again may help, so that generic handlers
+// need not know every code.
+type Kind string
+
+// Kinds with default HTTP statuses and retryability.
+const (
+	// KindTransient errors may succeed when retried, e.g. a dropped connection.
+	KindTransient Kind = "transient"
+	// KindClient errors are caused by the request of the caller.
+	KindClient Kind = "client"
+	// KindServer errors are caused by a bug or a failed dependency.
+	KindServer Kind = "server"
+	// KindSecurity errors are caused by missing or insufficient credentials.
+	KindSecurity Kind = "security"
+)
+
+var kindToHTTPStatus = map[Kind]int{
+	KindTransient: http.StatusServiceUnavailable,
+	KindClient:    http.StatusBadRequest,
+	KindServer:    http.StatusInternalServerError,
+	KindSecurity:  http.StatusForbidden,
+}
+
+// IsUserError reports whether err was caused by the caller, e.g. by bad input
+// or missing credentials, rather than by a fault of the system. The first kind
+// of err decides if it is set: KindClient and KindSecurity are user errors,
+// KindServer and KindTransient are system faults. Otherwise errors whose code
+// has a 4xx status according to HTTPStatus are user errors, so registered
+// codes are honored. Returns false if err is nil.
+func IsUserError(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch ErrorKind(err) {
+	case KindClient, KindSecurity:
+		return true
+	case KindServer, KindTransient:
+		return false
+	}
+	status := HTTPStatus(err)
+	return status >= http.StatusBadRequest && status < http.StatusInternalServerError
+}
@@ -0,0 +1,97 @@
+package e
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestErrorKind(t *testing.T) {
+	err := Wrap(Wrap(Foo()).SetKind(KindServer)).SetKind(KindTransient)
+	if got, want := ErrorKind(err), KindTransient; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorKind(Foo()); got != "" {
+		t.Errorf("expected no kind but got %q", got)
+	}
+
+	decoded, decodeErr := Decode(Encode(err))
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if got, want := ErrorKind(decoded), KindTransient; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestKindDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{
+			name:       "client",
+			err:        Foo().(Error).SetKind(KindClient),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "security",
+			err:        Foo().(Error).SetKind(KindSecurity),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:          "transient",
+			err:           Wrap(Foo()).SetKind(KindTransient),
+			wantStatus:    http.StatusServiceUnavailable,
+			wantRetryable: true,
+		},
+		{
+			name:       "code takes precedence",
+			err:        NewError(CodeNotFound, "cannot find bar").SetKind(KindClient),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "no kind",
+			err:        Foo(),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPStatus(tt.err); got != tt.wantStatus {
+				t.Errorf("\ngot:  %d\nwant: %d", got, tt.wantStatus)
+			}
+			if got := IsRetryable(tt.err); got != tt.wantRetryable {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestIsUserError(t *testing.T) {
+	RegisterCode("quota_exceeded", CodeInfo{HTTPStatus: http.StatusTooManyRequests})
+	t.Cleanup(func() { delete(codeInfos, "quota_exceeded") })
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"client code", NewError(CodeInvalid, "bad bar"), true},
+		{"registered code", Wrap(NewError("quota_exceeded", "tenant 12")), true},
+		{"server code", Foo(), false},
+		{"explicit kind overrides code", Wrap(Foo()).SetKind(KindClient), true},
+		{"explicit system fault", NewError(CodeNotFound, "bar config missing").SetKind(KindServer), false},
+		{"security kind", NewError("bad_token", "token expired").SetKind(KindSecurity), true},
+		{"timeout", NewError(CodeTimeout, "too slow"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUserError(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
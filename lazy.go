@@ -0,0 +1,64 @@
+package e
+
+import (
+	"sync"
+)
+
+// NewLazy behaves like NewError but only builds cause when the error is
+// formatted, so that expensive messages (e.g. serializing a large request)
+// cost nothing for errors which are swallowed. cause is called at most once.
+//
+// Usage:
+//
+//	return e.NewLazy(CodeInvalid, func() string {
+//		return fmt.Sprintf("cannot process request: %+v", req)
+//	})
+func NewLazy(code string, cause func() string) Error {
+	return runNewHooks(newImpl(getCallingFunc(2), code, &lazyError{text: lazyText{fn: cause}}))
+}
+
+// WrapLazy behaves like Wrap but only builds the additional info when the
+// error is formatted. info is called at most once. Returns nil if err is nil.
+func WrapLazy(err error, info func() string) Error {
+	if err == nil {
+		return nil
+	}
+
+	err = convert(err)
+	innerErr := &lazyError{text: lazyText{fn: info}, err: err}
+	return hooks.run(wrapImpl(getCallingFunc(2), err, innerErr))
+}
+
+// lazyText caches the result of fn.
+type lazyText struct {
+	once sync.Once
+	fn   func() string
+	text string
+}
+
+func (l *lazyText) String() string {
+	l.once.Do(func() {
+		l.text = l.fn()
+		l.fn = nil
+	})
+	return l.text
+}
+
+// lazyError is the root cause created by NewLazy if err is nil, or the
+// additional info added by WrapLazy otherwise. It is used by pointer so that
+// errors wrapping it stay comparable for errors.Is.
+type lazyError struct {
+	text lazyText
+	err  error
+}
+
+func (l *lazyError) Error() string {
+	if l.err == nil {
+		return l.text.String()
+	}
+	return "(" + l.text.String() + ")" + currentFormatter().separator() + l.err.Error() // localizer.Ignore
+}
+
+func (l *lazyError) Unwrap() error {
+	return l.err
+}
@@ -0,0 +1,42 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	expensive := func() string {
+		calls++
+		return "cannot process bar"
+	}
+
+	err := NewLazy(CodeInvalid, expensive)
+	wrapped := WrapLazy(err, func() string {
+		calls++
+		return "bar id: 1"
+	})
+	if calls != 0 {
+		t.Fatalf("expected messages not to be built before formatting but got %d calls", calls)
+	}
+	if got := ErrorCode(wrapped); got != CodeInvalid {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeInvalid)
+	}
+
+	want := "TestLazy: (bar id: 1): TestLazy: [invalid] cannot process bar"
+	for i := 0; i < 2; i++ {
+		if got := wrapped.Error(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected each message to be built once but got %d calls", calls)
+	}
+	if !errors.Is(wrapped, err) {
+		t.Errorf("expected wrapped error to match lazy error")
+	}
+	if WrapLazy(nil, expensive) != nil {
+		t.Errorf("expected WrapLazy of nil error to return nil")
+	}
+}
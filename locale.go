@@ -0,0 +1,102 @@
+package e
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var translator atomic.Pointer[func(lang, code, msg string) (string, bool)]
+
+// SetTranslator registers translate to localize the messages returned by
+// LocalizedMessage and written by WriteHTTPLocalized. translate is called with
+// a lowercase language tag such as "de" or "fr-ch", the first code of an error
+// and the message returned by ErrorMessage, and reports whether it has a
+// translation into that language. A nil translator removes the current one.
+//
+// Usage:
+//
+//	e.SetTranslator(func(lang, code, msg string) (string, bool) {
+//		translated, ok := catalog[lang][code]
+//		return translated, ok
+//	})
+func SetTranslator(translate func(lang, code, msg string) (string, bool)) {
+	if translate == nil {
+		translator.Store(nil)
+		return
+	}
+	translator.Store(&translate)
+}
+
+// LocalizedMessage returns the message of err translated into the most
+// preferred language of acceptLanguage, the value of an Accept-Language
+// header, for which the translator set with SetTranslator has a translation.
+// A language with a region such as "de-AT" falls back to "de" before less
+// preferred languages are tried. lang is the language of the translation
+// which was used, or empty if ErrorMessage(err) is returned untranslated.
+func LocalizedMessage(err error, acceptLanguage string) (msg, lang string) {
+	msg = ErrorMessage(err)
+	translate := translator.Load()
+	if err == nil || translate == nil {
+		return msg, ""
+	}
+
+	code := ErrorCode(err)
+	for _, lang := range preferredLanguages(acceptLanguage) {
+		if translated, ok := (*translate)(lang, code, msg); ok {
+			return translated, lang
+		}
+	}
+	return msg, ""
+}
+
+// preferredLanguages returns the lowercase language tags of an
+// Accept-Language header ordered by their quality, each followed by its
+// shorter prefixes. Wildcards and tags with a quality of 0 are skipped.
+func preferredLanguages(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	var langs []string
+	seen := make(map[string]bool)
+	for _, w := range tags {
+		for tag := w.tag; tag != ""; {
+			if !seen[tag] {
+				seen[tag] = true
+				langs = append(langs, tag)
+			}
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return langs
+}
@@ -0,0 +1,78 @@
+package e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPreferredLanguages(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", nil},
+		{"de", []string{"de"}},
+		{"de-AT, fr;q=0.5, en;q=0.8", []string{"de-at", "de", "en", "fr"}},
+		{"fr-CH, fr;q=0.9, *;q=0.5", []string{"fr-ch", "fr"}},
+		{"en;q=0, de;q=bad, it", []string{"it"}},
+	}
+	for _, tt := range tests {
+		if got := preferredLanguages(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q\ngot:  %q\nwant: %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	catalog := map[string]map[string]string{
+		"de": {CodeNotFound: "Bar existiert nicht"},
+		"fr": {CodeNotFound: "Bar n'existe pas"},
+	}
+	SetTranslator(func(lang, code, msg string) (string, bool) {
+		translated, ok := catalog[lang][code]
+		return translated, ok
+	})
+	t.Cleanup(func() { SetTranslator(nil) })
+
+	err := Wrap(NewError(CodeNotFound, "cannot find bar")).SetMessage("Bar does not exist")
+	tests := []struct {
+		acceptLanguage string
+		wantMsg        string
+		wantLang       string
+	}{
+		{"de-AT, en;q=0.5", "Bar existiert nicht", "de"},
+		{"it, fr;q=0.7, de;q=0.3", "Bar n'existe pas", "fr"},
+		{"it", "Bar does not exist", ""},
+		{"", "Bar does not exist", ""},
+	}
+	for _, tt := range tests {
+		msg, lang := LocalizedMessage(err, tt.acceptLanguage)
+		if msg != tt.wantMsg || lang != tt.wantLang {
+			t.Errorf("%q\ngot:  %q, %q\nwant: %q, %q", tt.acceptLanguage, msg, lang, tt.wantMsg, tt.wantLang)
+		}
+	}
+
+	t.Run("WriteHTTPLocalized", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/bars/1", nil)
+		req.Header.Set("Accept-Language", "de-DE,de;q=0.9")
+		rec := httptest.NewRecorder()
+		WriteHTTPLocalized(rec, req, err)
+
+		var body httpBody
+		if decodeErr := json.NewDecoder(rec.Body).Decode(&body); decodeErr != nil {
+			t.Fatal(decodeErr)
+		}
+		if body.Message != "Bar existiert nicht" {
+			t.Errorf("\ngot:  %q\nwant: %q", body.Message, "Bar existiert nicht")
+		}
+		if got := rec.Header().Get("Content-Language"); got != "de" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "de")
+		}
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("\ngot:  %d\nwant: %d", rec.Code, http.StatusNotFound)
+		}
+	})
+}
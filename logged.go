@@ -0,0 +1,44 @@
+package e
+
+// MarkLogged returns err marked as logged so that middleware at other layers
+// can skip logging it again. The mark survives further wrapping.
+// Returns nil if err is nil.
+//
+// Usage:
+//
+//	if err != nil && !e.IsLogged(err) {
+//		logger.Error(err)
+//		err = e.MarkLogged(err)
+//	}
+//	return err
+func MarkLogged(err error) error {
+	if err == nil || IsLogged(err) {
+		return err
+	}
+	return loggedError{err: err}
+}
+
+// IsLogged returns true if err or any error it wraps was marked with
+// MarkLogged.
+func IsLogged(err error) bool {
+	for err := range chain(err) {
+		if _, ok := err.(loggedError); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// loggedError is transparent: it does not change Error() or any introspection
+// of the error it wraps.
+type loggedError struct {
+	err error
+}
+
+func (l loggedError) Error() string {
+	return l.err.Error()
+}
+
+func (l loggedError) Unwrap() error {
+	return l.err
+}
@@ -0,0 +1,37 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMarkLogged(t *testing.T) {
+	t.Run("unmarked error is not logged", func(t *testing.T) {
+		if IsLogged(Foo()) {
+			t.Errorf("expected error not to be logged")
+		}
+	})
+	t.Run("mark survives wrapping", func(t *testing.T) {
+		err := Wrap(fmt.Errorf("outer: %w", Wrap(MarkLogged(Foo()))))
+		if !IsLogged(err) {
+			t.Errorf("expected error to be logged")
+		}
+	})
+	t.Run("mark is transparent", func(t *testing.T) {
+		inner := Foo()
+		err := Wrap(MarkLogged(inner))
+		if !errors.Is(err, inner) || ErrorCode(err) != CodeDatabase {
+			t.Errorf("expected marked error to behave like the original")
+		}
+		want := "TestMarkLogged.func3: Foo: [database_error] cannot foo"
+		if err.Error() != want {
+			t.Errorf("\ngot:  %q\nwant: %q", err, want)
+		}
+	})
+	t.Run("nil stays nil", func(t *testing.T) {
+		if err := MarkLogged(nil); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// Package logruserr logs errors from package e as structured logrus fields,
+// using the same keys as package zaperr.
+package logruserr
+
+import (
+	"github.com/kisunji/e"
+	"github.com/sirupsen/logrus"
+)
+
+// WithError returns an entry with an "error" field containing the error
+// string, code, kind, message, id, retryability, upstream, timestamp, ops,
+// infos, fields and stacktrace of err.
+//
+// Usage:
+//
+//	logruserr.WithError(logger, err).Error("cannot process bar")
+func WithError(logger logrus.FieldLogger, err error) *logrus.Entry {
+	if err == nil {
+		return logger.WithFields(nil)
+	}
+	return logger.WithField(logrus.ErrorKey, Fields(err))
+}
+
+// Fields returns the structured representation of err used by WithError.
+// Returns nil if err is nil.
+func Fields(err error) logrus.Fields {
+	if err == nil {
+		return nil
+	}
+
+	flat := e.Flatten(err)
+	fields := logrus.Fields{"error": flat.Error}
+	if flat.Code != "" {
+		fields["code"] = flat.Code
+	}
+	if flat.Kind != "" {
+		fields["kind"] = string(flat.Kind)
+	}
+	if flat.Message != "" {
+		fields["message"] = flat.Message
+	}
+	if flat.ID != "" {
+		fields["id"] = flat.ID
+	}
+	if flat.Retryable {
+		fields["retryable"] = true
+	}
+	if flat.Upstream != nil {
+		fields["upstream"] = *flat.Upstream
+	}
+	if !flat.Timestamp.IsZero() {
+		fields["timestamp"] = flat.Timestamp
+	}
+	if len(flat.Ops) > 0 {
+		fields["ops"] = flat.Ops
+	}
+	if len(flat.Infos) > 0 {
+		fields["infos"] = flat.Infos
+	}
+	if len(flat.Fields) > 0 {
+		fields["fields"] = flat.Fields
+	}
+	if flat.Stacktrace != "" {
+		fields["stacktrace"] = flat.Stacktrace
+	}
+	return fields
+}
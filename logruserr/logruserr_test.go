@@ -0,0 +1,58 @@
+package logruserr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kisunji/e"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestWithError(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	err := e.Wrap(e.NewError(e.CodeNotFound, "cannot find bar"), "bar id: 2hs8qh9").
+		SetMessage("Bar does not exist").
+		SetKind(e.KindClient).
+		SetField("bar_id", "2hs8qh9").
+		SetUpstream("bardb", "GET /bars", 404)
+	WithError(logger, err).Error("request failed")
+
+	got := hook.LastEntry().Data[logrus.ErrorKey]
+	want := logrus.Fields{
+		"error":      "TestWithError: (bar id: 2hs8qh9): TestWithError: [not_found] cannot find bar",
+		"code":       e.CodeNotFound,
+		"kind":       string(e.KindClient),
+		"message":    "Bar does not exist",
+		"ops":        []string{"TestWithError", "TestWithError"},
+		"infos":      []string{"bar id: 2hs8qh9"},
+		"upstream":   e.UpstreamInfo{System: "bardb", Endpoint: "GET /bars", Status: 404},
+		"fields":     map[string]interface{}{"bar_id": "2hs8qh9"},
+		"stacktrace": e.ErrorStacktrace(err),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestFieldsTimestamp(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	e.SetTimestamps(true)
+	e.SetTestClock(func() time.Time { return created })
+	t.Cleanup(func() {
+		e.SetTimestamps(false)
+		e.SetTestClock(nil)
+	})
+
+	if got := Fields(e.NewError(e.CodeNotFound, "cannot find bar"))["timestamp"]; got != created {
+		t.Errorf("\ngot:  %v\nwant: %v", got, created)
+	}
+}
+
+func TestFieldsNil(t *testing.T) {
+	if got := Fields(nil); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+}
@@ -0,0 +1,75 @@
+package e
+
+// ToMap returns the error string, code, kind, message, hint, documentation URL,
+// id, source, retryability, upstream, timestamp, ops, infos, fields and
+// stacktrace of err as a map which can be consumed by generic encoders such as
+// YAML, JSON and TOML encoders, e.g. to include errors in run reports. Keys of
+// attributes which are not set are omitted. Returns nil if err is nil.
+//
+// Usage:
+//
+//	report.Failures = append(report.Failures, e.ToMap(err))
+func ToMap(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+
+	flat := Flatten(err)
+	m := map[string]interface{}{"error": flat.Error}
+	if flat.Code != "" {
+		m["code"] = flat.Code
+	}
+	if flat.Kind != "" {
+		m["kind"] = string(flat.Kind)
+	}
+	if flat.Message != "" {
+		m["message"] = flat.Message
+	}
+	if flat.Hint != "" {
+		m["hint"] = flat.Hint
+	}
+	if flat.DocURL != "" {
+		m["docUrl"] = flat.DocURL
+	}
+	if flat.ID != "" {
+		m["id"] = flat.ID
+	}
+	if flat.Source != "" {
+		m["source"] = flat.Source
+	}
+	if flat.Retryable {
+		m["retryable"] = true
+	}
+	if flat.Upstream != nil {
+		upstream := map[string]interface{}{"system": flat.Upstream.System}
+		if flat.Upstream.Endpoint != "" {
+			upstream["endpoint"] = flat.Upstream.Endpoint
+		}
+		if flat.Upstream.Status != 0 {
+			upstream["status"] = flat.Upstream.Status
+		}
+		m["upstream"] = upstream
+	}
+	if !flat.Timestamp.IsZero() {
+		m["timestamp"] = flat.Timestamp
+	}
+	if len(flat.Ops) > 0 {
+		m["ops"] = flat.Ops
+	}
+	if len(flat.Infos) > 0 {
+		m["infos"] = flat.Infos
+	}
+	if len(flat.Fields) > 0 {
+		m["fields"] = flat.Fields
+	}
+	if flat.Stacktrace != "" {
+		m["stacktrace"] = flat.Stacktrace
+	}
+	return m
+}
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2,
+// gopkg.in/yaml.v3 and compatible packages by encoding e with ToMap.
+func (e errorImpl) MarshalYAML() (interface{}, error) {
+	return ToMap(e), nil
+}
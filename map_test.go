@@ -0,0 +1,43 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToMap(t *testing.T) {
+	if got := ToMap(nil); got != nil {
+		t.Errorf("expected nil map but got %v", got)
+	}
+
+	err := Wrap(NewError(CodeNotFound, "cannot find bar"), "bar id: 2hs8qh9").
+		SetMessage("Bar does not exist").
+		SetKind(KindClient).
+		SetField("bar_id", "2hs8qh9")
+
+	got := ToMap(err)
+	if _, ok := got["stacktrace"].(string); !ok {
+		t.Errorf("expected stacktrace but got %v", got["stacktrace"])
+	}
+	delete(got, "stacktrace")
+	want := map[string]interface{}{
+		"error":   err.Error(),
+		"code":    CodeNotFound,
+		"kind":    "client",
+		"message": "Bar does not exist",
+		"ops":     []string{"TestToMap", "TestToMap"},
+		"infos":   []string{"bar id: 2hs8qh9"},
+		"fields":  map[string]interface{}{"bar_id": "2hs8qh9"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+
+	yaml, marshalErr := err.(errorImpl).MarshalYAML()
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	if m := yaml.(map[string]interface{}); m["error"] != err.Error() {
+		t.Errorf("\ngot:  %v\nwant: %v", m["error"], err.Error())
+	}
+}
@@ -0,0 +1,56 @@
+package e
+
+// Mode is a profile of package-wide settings, set with SetMode.
+type Mode int
+
+const (
+	// ModeDefault restores the defaults of the package.
+	ModeDefault Mode = iota
+	// ModeDevelopment favors detail: stacktraces, callers and timestamps are
+	// recorded, so that the %+v verb prints the full trace of every error,
+	// and ExpectCode checks expected codes.
+	ModeDevelopment
+	// ModeProduction favors safety and size: stacktraces and callers are not
+	// recorded, Error() returns PublicString like with SetPublicMode, and
+	// InternalString collapses chains beyond ProductionMaxOps ops.
+	ModeProduction
+	// ModeTest favors determinism: stacktraces, callers and timestamps are
+	// not recorded, so that errors of golden tests do not change between
+	// runs, and ExpectCode checks expected codes. See SetTestClock and
+	// SetTestIDGenerator for deterministic timestamps and ids.
+	ModeTest
+)
+
+// ProductionMaxOps is the MaxOps of the Formatter set by ModeProduction.
+const ProductionMaxOps = 8
+
+// SetMode applies the settings of mode with SetStacktraces, SetCallers,
+// SetTimestamps, SetExpectations, SetPublicMode and SetFormatter, so that
+// verbosity can be changed with one switch, e.g. depending on the environment.
+// Settings made before SetMode are overridden; call the other functions after
+// SetMode to adjust single settings.
+//
+// Usage:
+//
+//	func main() {
+//		if os.Getenv("ENV") == "production" {
+//			e.SetMode(e.ModeProduction)
+//		} else {
+//			e.SetMode(e.ModeDevelopment)
+//		}
+//		...
+//	}
+func SetMode(mode Mode) {
+	development, production := mode == ModeDevelopment, mode == ModeProduction
+
+	SetStacktraces(!production && mode != ModeTest)
+	SetCallers(development)
+	SetTimestamps(development)
+	SetExpectations(development || mode == ModeTest)
+	SetPublicMode(production)
+	if production {
+		SetFormatter(Formatter{MaxOps: ProductionMaxOps})
+	} else {
+		SetFormatter(Formatter{})
+	}
+}
@@ -0,0 +1,87 @@
+package e
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetMode(t *testing.T) {
+	t.Cleanup(func() { SetMode(ModeDefault) })
+
+	t.Run("development", func(t *testing.T) {
+		SetMode(ModeDevelopment)
+		err := Wrap(NewError(CodeNotFound, "cannot find bar").SetMessage("Bar does not exist"))
+		if ErrorStacktrace(err) == "" {
+			t.Errorf("expected stacktrace")
+		}
+		if got := len(Callers(err)); got != 2 {
+			t.Errorf("expected 2 callers but got %d", got)
+		}
+		if ErrorTimestamp(err).IsZero() {
+			t.Errorf("expected timestamp")
+		}
+		if got, want := err.Error(), "TestSetMode.func2: TestSetMode.func2: [not_found] cannot find bar"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("production", func(t *testing.T) {
+		SetMode(ModeProduction)
+		err := NewError(CodeNotFound, "cannot find bar").SetMessage("Bar does not exist")
+		for range ProductionMaxOps + 1 {
+			err = Wrap(err)
+		}
+		if stack := ErrorStacktrace(err); stack != "" {
+			t.Errorf("expected no stacktrace but got %q", stack)
+		}
+		if callers := Callers(err); callers != nil {
+			t.Errorf("expected no callers but got %q", callers)
+		}
+		if got, want := err.Error(), "[not_found] Bar does not exist"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got := InternalString(err); !strings.Contains(got, "... (+2 more): ") {
+			t.Errorf("expected collapsed chain but got %q", got)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		SetMode(ModeDefault)
+		err := Wrap(Foo())
+		if ErrorStacktrace(err) == "" {
+			t.Errorf("expected stacktrace")
+		}
+		if callers := Callers(err); callers != nil {
+			t.Errorf("expected no callers but got %q", callers)
+		}
+		if got, want := err.Error(), "TestSetMode.func4: Foo: [database_error] cannot foo"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("test", func(t *testing.T) {
+		SetMode(ModeTest)
+		SetTimestamps(true)
+		SetTestClock(func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) })
+		t.Cleanup(func() {
+			SetTestClock(nil)
+			SetIDGenerator(nil)
+		})
+
+		var encoded []string
+		for range 2 {
+			SetTestIDGenerator("err-")
+			encoded = append(encoded, string(Encode(Wrap(Foo()))))
+		}
+		if encoded[0] != encoded[1] {
+			t.Errorf("expected deterministic encoding\nfirst:  %s\nsecond: %s", encoded[0], encoded[1])
+		}
+		want := `{"chain":[{"op":"TestSetMode.func5","timestamp":"2020-01-01T00:00:00Z"},` +
+			`{"op":"Foo","code":"database_error","id":"err-1","timestamp":"2020-01-01T00:00:00Z"},` +
+			`{"foreign":true,"text":"cannot foo"}]}`
+		if encoded[0] != want {
+			t.Errorf("\ngot:  %s\nwant: %s", encoded[0], want)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package e
+
+import (
+	"errors"
+	"strings"
+)
+
+// Parse reconstructs an Error from a string rendered by Error() in the
+// default format, e.g. to rehydrate errors scraped from plain-text logs. The
+// ops, codes and infos of the chain are recovered and the remaining text
+// becomes the root cause, so that Error() of the result returns s again.
+// Messages, fields and other attributes are not part of Error() and cannot be
+// recovered.
+//
+// Parsing is best-effort: text of the root cause which looks like an op,
+// e.g. "EOF: ", is parsed as one.
+//
+// Usage:
+//
+//	err, parseErr := e.Parse(line)
+//	// e.Ops(err) == []string{"Fizz", "Foo"}
+func Parse(s string) (Error, error) {
+	if s == "" {
+		return nil, NewError(CodeInvalid, "cannot parse empty error string")
+	}
+
+	var (
+		layers  []errorImpl
+		current errorImpl
+		started bool
+	)
+	// next starts a new layer if the current one already has a part which
+	// would be rendered after the part about to be parsed.
+	next := func(hasLater bool) {
+		if started && hasLater {
+			layers = append(layers, current)
+			current = errorImpl{}
+		}
+		started = true
+	}
+	for {
+		if op, rest, ok := parseOp(s); ok {
+			next(true)
+			current.op, s = op, rest
+			continue
+		}
+		if code, rest, ok := parseDelimited(s, "[", "] "); ok {
+			next(current.code != "" || current.info != "")
+			current.code, s = code, rest
+			continue
+		}
+		if info, rest, ok := parseDelimited(s, "(", "): "); ok {
+			next(current.info != "")
+			current.info, s = info, rest
+			continue
+		}
+		break
+	}
+	if started {
+		layers = append(layers, current)
+	}
+
+	var err error = errors.New(s)
+	for i := len(layers) - 1; i >= 0; i-- {
+		layers[i].err = err
+		err = layers[i]
+	}
+	if impl, ok := err.(errorImpl); ok {
+		return impl, nil
+	}
+	return errorImpl{err: err}, nil
+}
+
+// parseOp parses an op followed by the separator. Ops do not contain spaces or
+// colons, and only start with a parenthesis if they are methods with a pointer
+// receiver such as "(*Server).Handle". Other text in parentheses is an info.
+func parseOp(s string) (op, rest string, ok bool) {
+	i := strings.IndexAny(s, " :")
+	if i <= 0 || !strings.HasPrefix(s[i:], ": ") {
+		return "", s, false
+	}
+	op = s[:i]
+	if strings.HasPrefix(op, "(") && (!strings.HasPrefix(op, "(*") || strings.HasSuffix(op, ")")) {
+		return "", s, false
+	}
+	return op, s[i+len(": "):], true
+}
+
+// parseDelimited parses non-empty text between prefix and the first suffix.
+func parseDelimited(s, prefix, suffix string) (text, rest string, ok bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return "", s, false
+	}
+	i := strings.Index(s[len(prefix):], suffix)
+	if i <= 0 {
+		return "", s, false
+	}
+	return s[len(prefix) : len(prefix)+i], s[len(prefix)+i+len(suffix):], true
+}
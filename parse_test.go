@@ -0,0 +1,72 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	err := Wrap(Wrapf(Foo(), "bar id: %d", 7).SetCode(CodeNotFound), "outer")
+	parsed, parseErr := Parse(err.Error())
+	if parseErr != nil {
+		t.Fatal(parseErr)
+	}
+
+	if got, want := parsed.Error(), err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := Ops(parsed), Ops(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := Codes(parsed), Codes(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := Infos(parsed), Infos(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	t.Run("methods and closures", func(t *testing.T) {
+		parsed, _ := Parse("(*Server).Handle.func1: (tenant: 12): Store.Get: [timeout] context deadline exceeded")
+		if got, want := Ops(parsed), []string{"(*Server).Handle.func1", "Store.Get"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got, want := Infos(parsed), []string{"tenant: 12"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("plain text", func(t *testing.T) {
+		parsed, _ := Parse("connection refused")
+		if got := parsed.Error(); got != "connection refused" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "connection refused")
+		}
+		if got := Ops(parsed); got != nil {
+			t.Errorf("expected no ops but got %q", got)
+		}
+	})
+	t.Run("empty string", func(t *testing.T) {
+		if _, parseErr := Parse(""); ErrorCode(parseErr) != CodeInvalid {
+			t.Errorf("expected invalid error but got %v", parseErr)
+		}
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(Wrap(Wrapf(Foo(), "bar id: %d", 7), "outer").Error())
+	f.Add("(*T).M: [code] (info): Op: cause: more")
+	f.Add("[] (): : [a] b")
+	f.Fuzz(func(t *testing.T, s string) {
+		parsed, err := Parse(s)
+		if s == "" {
+			if err == nil {
+				t.Errorf("expected error for empty string")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := parsed.Error(); got != s {
+			t.Errorf("\ngot:  %q\nwant: %q", got, s)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package e
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Cause returns the wrapped error like Unwrap, so that Cause of
+// github.com/pkg/errors finds the root cause of chains mixing both packages.
+func (e errorImpl) Cause() error {
+	return e.err
+}
+
+// FromPkgErrors converts an error created with github.com/pkg/errors into an
+// Error, easing migration for codebases which use both packages. The messages
+// added by errors.Wrap and errors.WithMessage become infos and the stacktrace
+// recorded by the innermost error of the package is kept instead of capturing
+// a new one. The name of the calling function is added like with Wrap.
+// Returns nil if err is nil.
+//
+// Package e does not depend on github.com/pkg/errors; its errors are
+// recognized by their Cause and StackTrace methods.
+//
+// Usage:
+//
+//	if err := legacy.Do(); err != nil {
+//		return e.FromPkgErrors(err)
+//	}
+func FromPkgErrors(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		infos []string
+		st    *stack
+	)
+	// Causes are walked like chain walks Unwrap, stopping at the depth limit
+	// or when Cause forms a cycle.
+	root := err
+	saved, power, steps := root, 1, 0
+	for depth := 1; depth < depthLimit(); depth++ {
+		if _, ok := asImpl(root); ok {
+			break
+		}
+		if s := pkgStack(root); s != nil {
+			st = s
+		}
+		c, ok := root.(interface{ Cause() error })
+		if !ok || c.Cause() == nil {
+			break
+		}
+		text, separated := ownText(root, c.Cause())
+		if !separated {
+			break
+		}
+		if info := strings.TrimSuffix(text, ": "); info != "" {
+			infos = append(infos, info)
+		}
+		root = c.Cause()
+		if sameError(root, saved) {
+			break
+		}
+		if steps++; steps == power {
+			saved, power, steps = root, power*2, 0
+		}
+	}
+
+	inner := convert(root)
+	for i := len(infos) - 1; i >= 0; i-- {
+		inner = errorImpl{info: infos[i], err: inner, stack: st}
+	}
+	wrapped := wrapImpl(getCallingFunc(2), inner, inner)
+	if st != nil {
+		wrapped.stack = st
+	}
+	return hooks.run(wrapped)
+}
+
+// pkgStack returns the stack recorded by an error of github.com/pkg/errors,
+// whose StackTrace method returns a slice of program counters of a named
+// type.
+func pkgStack(err error) *stack {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	if out := m.Type().Out(0); out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	frames := m.Call(nil)[0]
+	if frames.Len() == 0 {
+		return nil
+	}
+	s := &stack{}
+	for ; s.n < frames.Len() && s.n < maxStackDepth; s.n++ {
+		s.pcs[s.n] = uintptr(frames.Index(s.n).Uint())
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+package e
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// The following types mimic github.com/pkg/errors.
+
+type pkgFrame uintptr
+
+type pkgStackTrace []pkgFrame
+
+func pkgCallers() pkgStackTrace {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	st := make(pkgStackTrace, n)
+	for i, pc := range pcs[:n] {
+		st[i] = pkgFrame(pc)
+	}
+	return st
+}
+
+type pkgFundamental struct {
+	msg   string
+	stack pkgStackTrace
+}
+
+func (f *pkgFundamental) Error() string             { return f.msg }
+func (f *pkgFundamental) StackTrace() pkgStackTrace { return f.stack }
+
+type pkgWithStack struct {
+	error
+	stack pkgStackTrace
+}
+
+func (w *pkgWithStack) Cause() error              { return w.error }
+func (w *pkgWithStack) Unwrap() error             { return w.error }
+func (w *pkgWithStack) StackTrace() pkgStackTrace { return w.stack }
+
+type pkgWithMessage struct {
+	cause error
+	msg   string
+}
+
+func (w *pkgWithMessage) Error() string { return w.msg + ": " + w.cause.Error() }
+func (w *pkgWithMessage) Cause() error  { return w.cause }
+func (w *pkgWithMessage) Unwrap() error { return w.cause }
+
+// pkgLoop is its own cause.
+type pkgLoop struct{}
+
+func (l *pkgLoop) Error() string { return "loop" }
+func (l *pkgLoop) Cause() error  { return l }
+
+// pkgDeep has an endless chain of causes.
+type pkgDeep struct{ n int }
+
+func (d pkgDeep) Error() string { return "deep" }
+func (d pkgDeep) Cause() error  { return pkgDeep{n: d.n + 1} }
+
+// pkgWrap behaves like errors.Wrap of github.com/pkg/errors.
+func pkgWrap(err error, msg string) error {
+	return &pkgWithStack{error: &pkgWithMessage{cause: err, msg: msg}, stack: pkgCallers()}
+}
+
+func pkgNew() error {
+	return &pkgFundamental{msg: "connection refused", stack: pkgCallers()}
+}
+
+func TestFromPkgErrors(t *testing.T) {
+	root := pkgNew()
+	err := FromPkgErrors(pkgWrap(pkgWrap(root, "cannot dial"), "cannot fetch bar"))
+
+	want := "TestFromPkgErrors: (cannot fetch bar): (cannot dial): connection refused"
+	if got := err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := Infos(err), []string{"cannot fetch bar", "cannot dial"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if !errors.Is(err, root) {
+		t.Errorf("expected root cause to be kept")
+	}
+	if stack := ErrorStacktrace(err); !strings.HasPrefix(stack, "github.com/kisunji/e.pkgNew()\n") {
+		t.Errorf("expected stacktrace of the root but got %q", stack)
+	}
+	if got := Wrap(err).Stacktrace(); got != ErrorStacktrace(err) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, ErrorStacktrace(err))
+	}
+
+	t.Run("Cause", func(t *testing.T) {
+		var cause error = Wrap(Foo())
+		for {
+			c, ok := cause.(interface{ Cause() error })
+			if !ok {
+				break
+			}
+			cause = c.Cause()
+		}
+		if got, want := cause.Error(), "cannot foo"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		if got, want := FromPkgErrors(&pkgLoop{}).Error(), "TestFromPkgErrors.func2: loop"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		SetMaxDepth(10)
+		t.Cleanup(func() { SetMaxDepth(0) })
+
+		var deep pkgDeep
+		if !errors.As(FromPkgErrors(pkgDeep{}), &deep) || deep.n != 9 {
+			t.Errorf("expected causes to be cut at the depth limit but got %d", deep.n)
+		}
+	})
+
+	if got := FromPkgErrors(nil); got != nil {
+		t.Errorf("expected nil but got %#v", got)
+	}
+}
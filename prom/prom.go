@@ -0,0 +1,60 @@
+// Package prom counts errors created by package e with Prometheus, labeled by
+// code and op.
+//
+// Usage:
+//
+//	counter := prom.NewCounter(prometheus.CounterOpts{Name: "app_errors_total"})
+//	prometheus.MustRegister(counter)
+//	e.OnNew(counter.Observe)
+package prom
+
+import (
+	"github.com/kisunji/e"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultName is used when NewCounter is called without a metric name.
+const DefaultName = "errors_total"
+
+// Counter is a prometheus.Collector which counts observed errors by the
+// "code" and "op" labels.
+type Counter struct {
+	vec *prometheus.CounterVec
+}
+
+// NewCounter constructs a Counter. opts.Help and opts.Name are defaulted if
+// left blank.
+func NewCounter(opts prometheus.CounterOpts) *Counter {
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if opts.Help == "" {
+		opts.Help = "Number of errors created, partitioned by code and op."
+	}
+	return &Counter{
+		vec: prometheus.NewCounterVec(opts, []string{"code", "op"}),
+	}
+}
+
+// Observe increments the counter for the code and outermost op of err. It can
+// be registered directly with e.OnNew.
+func (c *Counter) Observe(err e.Error) {
+	if err == nil {
+		return
+	}
+	var op string
+	if ops := e.Ops(err); len(ops) > 0 {
+		op = ops[0]
+	}
+	c.vec.WithLabelValues(e.ErrorCode(err), op).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Counter) Describe(ch chan<- *prometheus.Desc) {
+	c.vec.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Counter) Collect(ch chan<- prometheus.Metric) {
+	c.vec.Collect(ch)
+}
@@ -0,0 +1,30 @@
+package prom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kisunji/e"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter(prometheus.CounterOpts{})
+	e.OnNew(counter.Observe)
+
+	for i := 0; i < 2; i++ {
+		_ = e.NewError(e.CodeNotFound, "cannot find bar")
+	}
+	_ = e.Wrap(e.NewError(e.CodeTimeout, "too slow"))
+
+	want := `
+# HELP errors_total Number of errors created, partitioned by code and op.
+# TYPE errors_total counter
+errors_total{code="not_found",op="TestCounter"} 2
+errors_total{code="timeout",op="TestCounter"} 1
+`
+	if err := testutil.CollectAndCompare(counter, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
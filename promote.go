@@ -0,0 +1,64 @@
+package e
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type promotionRule struct {
+	opPrefix string
+	from, to string
+}
+
+var (
+	promotionRulesMu sync.Mutex
+	promotionRules   atomic.Pointer[[]promotionRule]
+)
+
+// SetPromotionRule makes Wrap escalate the code of errors with code from to
+// code to when they are wrapped by a function whose op starts with opPrefix,
+// e.g. so that timeouts of dependencies surface as CodeUnavailable at the
+// handler layer without each handler converting them by hand. Codes are
+// compared like IsCode. Rules apply only when the wrapped error already has
+// a code, and the first matching rule in the order they were set wins. Setting
+// a rule for the same opPrefix and from replaces it and an empty to removes
+// it.
+//
+// Usage:
+//
+//	func init() {
+//		e.SetPromotionRule("(*Handler).", e.CodeTimeout, e.CodeUnavailable)
+//	}
+func SetPromotionRule(opPrefix, from, to string) {
+	promotionRulesMu.Lock()
+	defer promotionRulesMu.Unlock()
+
+	var updated []promotionRule
+	if rules := promotionRules.Load(); rules != nil {
+		for _, rule := range *rules {
+			if rule.opPrefix != opPrefix || rule.from != from {
+				updated = append(updated, rule)
+			}
+		}
+	}
+	if to != "" {
+		updated = append(updated, promotionRule{opPrefix: opPrefix, from: from, to: to})
+	}
+	promotionRules.Store(&updated)
+}
+
+// promotedCode returns the code which an error with code should get when it
+// is wrapped by op, or an empty string if no rule applies.
+func promotedCode(op, code string) string {
+	rules := promotionRules.Load()
+	if rules == nil || code == "" {
+		return ""
+	}
+	for _, rule := range *rules {
+		if strings.HasPrefix(op, rule.opPrefix) && code == canonicalCode(rule.from) {
+			return rule.to
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package e
+
+import "testing"
+
+func fetchBar() error {
+	return NewError(CodeTimeout, "deadline exceeded")
+}
+
+func handleBar() error {
+	return Wrap(fetchBar())
+}
+
+func handleBarTwice() error {
+	return Wrap(handleBar())
+}
+
+func TestSetPromotionRule(t *testing.T) {
+	t.Cleanup(func() { promotionRules.Store(nil) })
+	SetPromotionRule("handle", CodeTimeout, CodeUnavailable)
+
+	err := handleBar()
+	if got := ErrorCode(err); got != CodeUnavailable {
+		t.Errorf("\ngot:  %q\nwant: %q", got, CodeUnavailable)
+	}
+	if want := "handleBar: [unavailable] fetchBar: [timeout] deadline exceeded"; err.Error() != want {
+		t.Errorf("\ngot:  %q\nwant: %q", err, want)
+	}
+	if got := ErrorCode(Wrap(fetchBar())); got != CodeTimeout {
+		t.Errorf("expected code of other ops to be kept but got %q", got)
+	}
+	if want := "handleBarTwice: handleBar: [unavailable] fetchBar: [timeout] deadline exceeded"; handleBarTwice().Error() != want {
+		t.Errorf("\ngot:  %q\nwant: %q", handleBarTwice(), want)
+	}
+
+	SetPromotionRule("handle", CodeTimeout, "")
+	if got := ErrorCode(handleBar()); got != CodeTimeout {
+		t.Errorf("expected removed rule to not apply but got %q", got)
+	}
+}
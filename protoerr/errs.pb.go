@@ -0,0 +1,193 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: protoerr/errs.proto
+
+package protoerr
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error carries an error from package e inside protobuf envelopes. It is
+// created by protoerr.ToProto and read by protoerr.FromProto.
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// First code of the error chain.
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// First user-friendly message of the error chain.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Ops of the error chain, ordered from outermost to innermost.
+	Ops []string `protobuf:"bytes,3,rep,name=ops,proto3" json:"ops,omitempty"`
+	// Fields of the error chain as merged by e.ErrorFields.
+	Fields map[string]*structpb.Value `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Whether the failed operation can be retried.
+	Retryable bool `protobuf:"varint,5,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	// Unique id of the error occurrence.
+	Id string `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
+	// Error() of the original error.
+	Text          string `protobuf:"bytes,7,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_protoerr_errs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_protoerr_errs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_protoerr_errs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetOps() []string {
+	if x != nil {
+		return x.Ops
+	}
+	return nil
+}
+
+func (x *Error) GetFields() map[string]*structpb.Value {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *Error) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *Error) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Error) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+var File_protoerr_errs_proto protoreflect.FileDescriptor
+
+const file_protoerr_errs_proto_rawDesc = "" +
+	"\n" +
+	"\x13protoerr/errs.proto\x12\fkisunji.e.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x95\x02\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x10\n" +
+	"\x03ops\x18\x03 \x03(\tR\x03ops\x127\n" +
+	"\x06fields\x18\x04 \x03(\v2\x1f.kisunji.e.v1.Error.FieldsEntryR\x06fields\x12\x1c\n" +
+	"\tretryable\x18\x05 \x01(\bR\tretryable\x12\x0e\n" +
+	"\x02id\x18\x06 \x01(\tR\x02id\x12\x12\n" +
+	"\x04text\x18\a \x01(\tR\x04text\x1aQ\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01B\x1fZ\x1dgithub.com/kisunji/e/protoerrb\x06proto3"
+
+var (
+	file_protoerr_errs_proto_rawDescOnce sync.Once
+	file_protoerr_errs_proto_rawDescData []byte
+)
+
+func file_protoerr_errs_proto_rawDescGZIP() []byte {
+	file_protoerr_errs_proto_rawDescOnce.Do(func() {
+		file_protoerr_errs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protoerr_errs_proto_rawDesc), len(file_protoerr_errs_proto_rawDesc)))
+	})
+	return file_protoerr_errs_proto_rawDescData
+}
+
+var file_protoerr_errs_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_protoerr_errs_proto_goTypes = []any{
+	(*Error)(nil),          // 0: kisunji.e.v1.Error
+	nil,                    // 1: kisunji.e.v1.Error.FieldsEntry
+	(*structpb.Value)(nil), // 2: google.protobuf.Value
+}
+var file_protoerr_errs_proto_depIdxs = []int32{
+	1, // 0: kisunji.e.v1.Error.fields:type_name -> kisunji.e.v1.Error.FieldsEntry
+	2, // 1: kisunji.e.v1.Error.FieldsEntry.value:type_name -> google.protobuf.Value
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_protoerr_errs_proto_init() }
+func file_protoerr_errs_proto_init() {
+	if File_protoerr_errs_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protoerr_errs_proto_rawDesc), len(file_protoerr_errs_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protoerr_errs_proto_goTypes,
+		DependencyIndexes: file_protoerr_errs_proto_depIdxs,
+		MessageInfos:      file_protoerr_errs_proto_msgTypes,
+	}.Build()
+	File_protoerr_errs_proto = out.File
+	file_protoerr_errs_proto_goTypes = nil
+	file_protoerr_errs_proto_depIdxs = nil
+}
@@ -0,0 +1,10 @@
+module github.com/kisunji/e/protoerr
+
+go 1.24
+
+require (
+	github.com/kisunji/e v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.36.12
+)
+
+replace github.com/kisunji/e => ../
@@ -0,0 +1,131 @@
+// Package protoerr converts errors from package e to and from an Error
+// protobuf message, so that they can travel inside existing protobuf envelopes
+// such as the results exchanged by asynchronous workers.
+//
+//	// producer
+//	result.Error = protoerr.ToProto(err)
+//
+//	// consumer
+//	if err := protoerr.FromProto(result.Error); err != nil {
+//		return e.Wrap(err)
+//	}
+package protoerr
+
+//go:generate protoc --proto_path=.. --go_out=.. --go_opt=paths=source_relative protoerr/errs.proto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kisunji/e"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToProto converts err into an Error message with the first code, message and
+// id, the retryability, ops and fields of the error chain, and the error
+// string. Field values which cannot be represented as a structpb.Value are
+// converted to strings with fmt. Returns nil if err is nil.
+func ToProto(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	pb := &Error{
+		Code:      e.ErrorCode(err),
+		Message:   e.ErrorMessage(err),
+		Ops:       e.Ops(err),
+		Retryable: e.IsRetryable(err),
+		Id:        e.ErrorID(err),
+		Text:      err.Error(),
+	}
+	if fields := e.ErrorFields(err); len(fields) > 0 {
+		pb.Fields = make(map[string]*structpb.Value, len(fields))
+		for k, v := range fields {
+			value, convErr := structpb.NewValue(v)
+			if convErr != nil {
+				value = structpb.NewStringValue(fmt.Sprint(v))
+			}
+			pb.Fields[k] = value
+		}
+	}
+	return pb
+}
+
+// FromProto converts an Error message created by ToProto back into an error
+// which can be introspected with e.ErrorCode, e.ErrorMessage, e.ErrorID,
+// e.IsRetryable and e.ErrorFields. Use Ops to retrieve the ops of the original
+// error chain. Returns nil if pb is nil.
+func FromProto(pb *Error) error {
+	if pb == nil {
+		return nil
+	}
+
+	remote := &remoteError{
+		code:      pb.GetCode(),
+		message:   pb.GetMessage(),
+		ops:       pb.GetOps(),
+		retryable: pb.GetRetryable(),
+		id:        pb.GetId(),
+		text:      pb.GetText(),
+	}
+	if len(pb.GetFields()) > 0 {
+		remote.fields = make(map[string]interface{}, len(pb.GetFields()))
+		for k, v := range pb.GetFields() {
+			remote.fields[k] = v.AsInterface()
+		}
+	}
+	return remote
+}
+
+// Ops returns the ops recorded since an error converted with FromProto was
+// wrapped, followed by the ops of its original error chain. Otherwise it
+// behaves like e.Ops.
+func Ops(err error) []string {
+	ops := e.Ops(err)
+	var remote *remoteError
+	if errors.As(err, &remote) {
+		ops = append(ops, remote.ops...)
+	}
+	return ops
+}
+
+// remoteError implements e.ClientFacing, e.Retrier, e.HasID and e.HasFields
+// so it can be introspected with e.ErrorCode, e.ErrorMessage, e.IsRetryable,
+// e.ErrorID and e.ErrorFields. It is used by pointer so that errors wrapping it
+// stay comparable for errors.Is.
+type remoteError struct {
+	code      string
+	message   string
+	ops       []string
+	retryable bool
+	id        string
+	text      string
+	fields    map[string]interface{}
+}
+
+func (r *remoteError) Error() string {
+	if r.text == "" {
+		return e.PublicString(r)
+	}
+	return r.text
+}
+
+func (r *remoteError) ClientCode() string {
+	return r.code
+}
+
+func (r *remoteError) ClientMessage() string {
+	return r.message
+}
+
+func (r *remoteError) Retryable() bool {
+	return r.retryable
+}
+
+func (r *remoteError) ID() string {
+	return r.id
+}
+
+func (r *remoteError) Fields() map[string]interface{} {
+	return r.fields
+}
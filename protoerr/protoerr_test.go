@@ -0,0 +1,61 @@
+package protoerr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kisunji/e"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRoundTrip(t *testing.T) {
+	err := e.Wrap(e.NewError("quota_exceeded", "too many bars for tenant 12")).
+		SetMessage("Please slow down").
+		SetID("abc123").
+		SetRetryable(true).
+		SetField("tenant", 12).
+		SetField("token", e.Secret("hunter2"))
+
+	b, marshalErr := proto.Marshal(ToProto(err))
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	var pb Error
+	if unmarshalErr := proto.Unmarshal(b, &pb); unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	received := e.Wrap(FromProto(&pb))
+
+	if got, want := received.Error(), "TestRoundTrip: "+err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := e.ErrorCode(received); got != "quota_exceeded" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "quota_exceeded")
+	}
+	if got := e.ErrorMessage(received); got != "Please slow down" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "Please slow down")
+	}
+	if got := e.ErrorID(received); got != "abc123" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "abc123")
+	}
+	if !e.IsRetryable(received) {
+		t.Errorf("expected received error to be retryable")
+	}
+	wantFields := map[string]interface{}{"tenant": float64(12), "token": e.Redacted}
+	if got := e.ErrorFields(received); !reflect.DeepEqual(got, wantFields) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, wantFields)
+	}
+	wantOps := []string{"TestRoundTrip", "TestRoundTrip", "TestRoundTrip"}
+	if got := Ops(received); !reflect.DeepEqual(got, wantOps) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, wantOps)
+	}
+}
+
+func TestNil(t *testing.T) {
+	if ToProto(nil) != nil {
+		t.Errorf("expected nil message")
+	}
+	if FromProto(nil) != nil {
+		t.Errorf("expected nil error")
+	}
+}
@@ -0,0 +1,39 @@
+package e
+
+import "sync/atomic"
+
+var publicMode atomic.Bool
+
+// SetPublicMode makes Error() of every Error return PublicString instead of
+// the full error stack when enabled, so that internal details such as ops and
+// causes cannot leak into responses by accident. Use InternalString to log
+// the full error stack while public mode is enabled.
+func SetPublicMode(enabled bool) {
+	publicMode.Store(enabled)
+}
+
+// PublicString returns only the client-facing parts of err: its first code and
+// first message, e.g. "[not_found] Bar does not exist". The message is
+// redacted like Error(). Returns an empty string if err is nil.
+func PublicString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	code, msg := ErrorCode(err), redact(ErrorMessage(err))
+	switch {
+	case code != "" && msg != "":
+		return "[" + code + "] " + msg // localizer.Ignore
+	case code != "":
+		return "[" + code + "]" // localizer.Ignore
+	case msg != "":
+		return msg
+	}
+	return "unexpected error"
+}
+
+// InternalString returns the full error stack of err like Error() does outside
+// of public mode. Returns an empty string if err is nil.
+func InternalString(err error) string {
+	return Format(err, currentFormatter())
+}
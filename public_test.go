@@ -0,0 +1,65 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPublicString(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "code and message",
+			err:  Wrap(Foo()).SetMessage("Please try again"),
+			want: "[database_error] Please try again",
+		},
+		{
+			name: "code only",
+			err:  Foo(),
+			want: "[database_error]",
+		},
+		{
+			name: "nothing client-facing",
+			err:  errors.New("secret internals"),
+			want: "unexpected error",
+		},
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PublicString(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicMode(t *testing.T) {
+	err := Wrap(FizzBuzzWhiz()).SetMessage("Please try again")
+	internal := err.Error()
+
+	SetPublicMode(true)
+	t.Cleanup(func() { SetPublicMode(false) })
+
+	if got, want := err.Error(), "[database_error] Please try again"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := InternalString(err); got != internal {
+		t.Errorf("\ngot:  %q\nwant: %q", got, internal)
+	}
+}
+
+func TestInternalString(t *testing.T) {
+	for _, err := range []error{Foo(), Fizz(), FizzBuzz(), FizzBuzzWhiz(), validateUser("", 1)} {
+		if got := InternalString(err); got != err.Error() {
+			t.Errorf("\ngot:  %q\nwant: %q", got, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CodePanic is the code of errors created by FromPanic.
+const CodePanic = "panic"
+
+// FromPanic converts a value recovered from a panic into an Error with
+// CodePanic and KindServer, so that panics can be written and reported like
+// any other error. A recovered error becomes the cause so that errors.Is and
+// errors.As still find it. The stacktrace includes the frames which
+// panicked if FromPanic is called by the deferred function which recovered.
+//
+// op names the operation which panicked, such as an HTTP route or a gRPC
+// method, since the name of the function which recovered is not meaningful.
+//
+// Usage:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			err = e.FromPanic("ProcessJob", r)
+//		}
+//	}()
+func FromPanic(op string, recovered interface{}) Error {
+	cause, ok := recovered.(error)
+	if !ok {
+		cause = errors.New(fmt.Sprint(recovered))
+	}
+	created := newImpl(op, CodePanic, cause)
+	created.kind = KindServer
+	return runNewHooks(created)
+}
+
+// RecoverHTTP returns a handler which converts panics of next into errors with
+// FromPanic, reports them with Report and writes them with WriteHTTP, so that
+// panics and returned errors share one reporting path. The op is the pattern
+// of the route which matched the request, or its method and path otherwise.
+// http.ErrAbortHandler is not recovered.
+//
+// Usage:
+//
+//	http.ListenAndServe(addr, e.RecoverHTTP(mux))
+func RecoverHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			op := r.Pattern
+			if op == "" {
+				op = r.Method + " " + r.URL.Path
+			}
+			err := FromPanic(op, recovered)
+			Report(r.Context(), err)
+			WriteHTTP(w, err)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
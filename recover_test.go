@@ -0,0 +1,73 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFromPanic(t *testing.T) {
+	panicking := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = FromPanic("ProcessJob", r)
+			}
+		}()
+		var bars []string
+		_ = bars[1]
+		return nil
+	}
+
+	err := panicking()
+	if got, want := err.Error(), "ProcessJob: [panic] runtime error: index out of range [1] with length 0"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorKind(err); got != KindServer {
+		t.Errorf("\ngot:  %q\nwant: %q", got, KindServer)
+	}
+	if stack := ErrorStacktrace(err); !strings.Contains(stack, "TestFromPanic.func1()") {
+		t.Errorf("expected panicking function in stacktrace but got %q", stack)
+	}
+
+	if got := FromPanic("ProcessJob", errSentinel); !errors.Is(got, errSentinel) {
+		t.Errorf("expected recovered error to be the cause of %q", got)
+	}
+}
+
+func TestRecoverHTTP(t *testing.T) {
+	t.Cleanup(resetReporters)
+	var reported []string
+	RegisterReporter(ReporterFunc(func(ctx context.Context, err error, s Severity) {
+		reported = append(reported, err.Error())
+	}), SeverityInfo)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /bars/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("bar is nil")
+	})
+	handler := RecoverHTTP(mux)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bars/2hs8qh9", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("\ngot:  %d\nwant: %d", rec.Code, http.StatusInternalServerError)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"code":"panic"`) {
+		t.Errorf("expected panic code in body but got %s", body)
+	}
+	if want := []string{"GET /bars/{id}: [panic] bar is nil"}; len(reported) != 1 || reported[0] != want[0] {
+		t.Errorf("\ngot:  %q\nwant: %q", reported, want)
+	}
+
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Errorf("expected ErrAbortHandler to be re-panicked but got %v", r)
+		}
+	}()
+	RecoverHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,88 @@
+package e
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// Redacted replaces sensitive values in Error() and JSON output.
+const Redacted = "[REDACTED]"
+
+// SecretValue holds a sensitive value which is rendered as Redacted by fmt
+// (with any verb) and encoding/json. Use Reveal to access the value.
+type SecretValue struct {
+	value interface{}
+}
+
+// Secret wraps a sensitive value such as a token or password so that it can
+// be passed to NewErrorf, Wrapf or SetField without leaking into logs.
+//
+// Usage:
+//
+//	return e.NewErrorf(CodeInvalid, "invalid token %s", e.Secret(token)).
+//		SetField("token", e.Secret(token))
+//	// "Foo: [invalid] invalid token [REDACTED]"
+func Secret(value interface{}) SecretValue {
+	return SecretValue{value: value}
+}
+
+// Reveal returns the sensitive value. It should only be used for debugging.
+func (s SecretValue) Reveal() interface{} {
+	return s.value
+}
+
+func (s SecretValue) String() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter so that every verb, including %#v, is
+// redacted.
+func (s SecretValue) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(Redacted))
+}
+
+func (s SecretValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+type redactor struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+var (
+	redactorsMu sync.Mutex
+	redactors   atomic.Value // []redactor
+)
+
+// RegisterRedactor replaces every match of pattern with replacement whenever
+// an Error is formatted with Error(), encoded with Encode or printed with
+// FprintTree, including its infos and messages. The replacement
+// may contain $1-style references to submatches and should not itself match
+// pattern. RegisterRedactor is intended to be called during initialization.
+//
+// Usage:
+//
+//	func init() {
+//		e.RegisterRedactor(regexp.MustCompile(`password=\S+`), "password="+e.Redacted)
+//	}
+func RegisterRedactor(pattern *regexp.Regexp, replacement string) {
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+
+	current, _ := redactors.Load().([]redactor)
+	updated := make([]redactor, len(current), len(current)+1)
+	copy(updated, current)
+	redactors.Store(append(updated, redactor{pattern: pattern, replacement: replacement}))
+}
+
+func redact(s string) string {
+	current, _ := redactors.Load().([]redactor)
+	for _, r := range current {
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+	return s
+}
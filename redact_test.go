@@ -0,0 +1,88 @@
+package e
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	t.Run("redacted in Error()", func(t *testing.T) {
+		for _, verb := range []string{"%v", "%s", "%+v", "%#v", "%q", "%d"} {
+			err := NewErrorf(CodeInvalid, "invalid token "+verb, Secret("hunter2"))
+			want := "TestSecret.func1: [invalid] invalid token [REDACTED]"
+			if err.Error() != want {
+				t.Errorf("\ngot:  %q\nwant: %q", err, want)
+			}
+		}
+	})
+	t.Run("redacted in JSON", func(t *testing.T) {
+		err := NewError(CodeInvalid, "invalid token").SetField("token", Secret("hunter2"))
+		b, jsonErr := json.Marshal(ErrorFields(err))
+		if jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+		if string(b) != `{"token":"[REDACTED]"}` {
+			t.Errorf("unexpected JSON: %s", b)
+		}
+	})
+	t.Run("value can be revealed", func(t *testing.T) {
+		if got := Secret("hunter2").Reveal(); got != "hunter2" {
+			t.Errorf("\ngot:  %v\nwant: %v", got, "hunter2")
+		}
+	})
+}
+
+func TestRegisterRedactor(t *testing.T) {
+	t.Cleanup(func() { redactors.Store([]redactor(nil)) })
+	RegisterRedactor(regexp.MustCompile(`password=\S+`), "password="+Redacted)
+
+	inner := fmt.Errorf("dial postgres://u@db?password=hunter2 failed: %w", errors.New("refused"))
+	err := Wrap(inner)
+
+	want := "TestRegisterRedactor: dial postgres://u@db?password=[REDACTED] failed: refused"
+	if err.Error() != want {
+		t.Errorf("\ngot:  %q\nwant: %q", err, want)
+	}
+
+	decoded, decodeErr := Decode(Encode(err))
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if decoded.Error() != want {
+		t.Errorf("expected encoded error to be redacted but got %q", decoded)
+	}
+
+	t.Run("infos and messages", func(t *testing.T) {
+		err := Wrap(Foo(), "password=hunter2").SetMessage("Login with password=hunter2 failed")
+		if encoded := Encode(err); bytes.Contains(encoded, []byte("hunter2")) {
+			t.Errorf("expected encoded error to be redacted but got %s", encoded)
+		}
+		var sb strings.Builder
+		withField := Wrap(err).SetField("dsn", "postgres://u@db?password=hunter2")
+		if err := (RenderOptions{Fields: true}).FprintTree(&sb, withField); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(sb.String(), "hunter2") {
+			t.Errorf("expected tree to be redacted but got %q", sb.String())
+		}
+	})
+
+	t.Run("public", func(t *testing.T) {
+		err := Wrap(Foo()).SetMessage("Login with password=hunter2 failed")
+		want := "[database_error] Login with password=[REDACTED] failed"
+		if got := PublicString(err); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+
+		SetPublicMode(true)
+		t.Cleanup(func() { SetPublicMode(false) })
+		if got := err.Error(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package e
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	codeInfosMu sync.RWMutex
+	codeInfos   = make(map[string]CodeInfo)
+)
+
+// CodeInfo documents an application error code.
+type CodeInfo struct {
+	// Description explains when the code is used.
+	Description string
+
+	// DefaultMessage is returned by ErrorMessage for errors with the code
+	// which have no message, so that end-users never see a blank message.
+	DefaultMessage string
+
+	// HTTPStatus is returned by HTTPStatus for errors with the code.
+	// The default mapping is used if HTTPStatus is 0.
+	HTTPStatus int
+
+	// Retryable makes IsRetryable report errors with the code as retryable,
+	// unless SetRetryable(false) was called on them. A CodeInfo attached with
+	// Attach can only turn it on.
+	Retryable bool
+
+	// Severity is returned by ErrorSeverity for errors with the code.
+	// The severity is derived from the error if Severity is 0.
+	Severity Severity
+
+	// DocURL links to documentation such as a runbook or knowledge base
+	// article for errors with the code. It is returned by ErrorDocURL.
+	DocURL string
+
+	// GRPCCode is the google.golang.org/grpc/codes.Code used by package
+	// grpcmw for errors with the code. The default mapping is used if
+	// GRPCCode is 0.
+	GRPCCode uint32
+}
+
+// RegisterCode documents code and changes how it is handled by ErrorMessage,
+// HTTPStatus, IsRetryable, ErrorSeverity and package grpcmw, so that the
+// policy for a code is declared in one place. A code registered for a
+// namespace such as "storage" also applies to hierarchical codes within it
+// such as "storage.postgres.timeout", unless they are registered themselves.
+// Registered codes are also picked up by cmd/errscatalog to generate a catalog
+// of error codes.
+//
+// Usage:
+//
+//	const CodeQuotaExceeded = "quota_exceeded"
+//
+//	func init() {
+//		e.RegisterCode(CodeQuotaExceeded, e.CodeInfo{
+//			Description:    "The tenant has used up its request quota.",
+//			DefaultMessage: "Too many requests, please try again later",
+//			HTTPStatus:     http.StatusTooManyRequests,
+//			Retryable:      true,
+//			GRPCCode:       uint32(codes.ResourceExhausted),
+//		})
+//	}
+//
+// Single errors can override the registered policy by attaching a CodeInfo
+// with Attach; see ErrorCodeInfo.
+func RegisterCode(code string, info CodeInfo) {
+	codeInfosMu.Lock()
+	defer codeInfosMu.Unlock()
+	codeInfos[code] = info
+}
+
+// lookupNearestCode returns the CodeInfo registered for code or for its
+// closest registered parent.
+func lookupNearestCode(code string) (CodeInfo, bool) {
+	for ; code != ""; code = parentCode(code) {
+		if info, ok := LookupCode(code); ok {
+			return info, true
+		}
+	}
+	return CodeInfo{}, false
+}
+
+// ErrorCodeInfo returns the CodeInfo which applies to err: the one registered
+// for the first code of err or for its closest registered parent, with the
+// non-zero fields of a CodeInfo attached to err with Attach taking precedence.
+// Returns false if neither exists.
+//
+// Usage:
+//
+//	// page on this failure even though its code is usually harmless
+//	return e.Attach(err, e.CodeInfo{Severity: e.SeverityCritical})
+func ErrorCodeInfo(err error) (CodeInfo, bool) {
+	info, ok := lookupNearestCode(ErrorCode(err))
+	if override, found := Detail[CodeInfo](err); found {
+		info, ok = info.override(override), true
+	}
+	return info, ok
+}
+
+// override returns info with the non-zero fields of o.
+func (info CodeInfo) override(o CodeInfo) CodeInfo {
+	if o.Description != "" {
+		info.Description = o.Description
+	}
+	if o.DefaultMessage != "" {
+		info.DefaultMessage = o.DefaultMessage
+	}
+	if o.HTTPStatus != 0 {
+		info.HTTPStatus = o.HTTPStatus
+	}
+	if o.Retryable {
+		info.Retryable = true
+	}
+	if o.Severity != 0 {
+		info.Severity = o.Severity
+	}
+	if o.DocURL != "" {
+		info.DocURL = o.DocURL
+	}
+	if o.GRPCCode != 0 {
+		info.GRPCCode = o.GRPCCode
+	}
+	return info
+}
+
+// LookupCode returns the CodeInfo registered for code.
+func LookupCode(code string) (CodeInfo, bool) {
+	codeInfosMu.RLock()
+	defer codeInfosMu.RUnlock()
+	info, ok := codeInfos[code]
+	return info, ok
+}
+
+// RegisteredCodes returns the codes registered with RegisterCode in sorted
+// order.
+func RegisteredCodes() []string {
+	codeInfosMu.RLock()
+	defer codeInfosMu.RUnlock()
+	codes := make([]string, 0, len(codeInfos))
+	for code := range codeInfos {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,135 @@
+package e
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterCode(t *testing.T) {
+	RegisterCode("quota_exceeded", CodeInfo{
+		Description: "The tenant has used up its request quota.",
+		HTTPStatus:  http.StatusTooManyRequests,
+		Retryable:   true,
+	})
+	RegisterCode(CodeNotFound, CodeInfo{Description: "The resource does not exist."})
+	t.Cleanup(func() {
+		delete(codeInfos, "quota_exceeded")
+		delete(codeInfos, CodeNotFound)
+	})
+
+	err := Wrap(NewError("quota_exceeded", "tenant 12 made too many requests"))
+	if got := HTTPStatus(err); got != http.StatusTooManyRequests {
+		t.Errorf("\ngot:  %v\nwant: %v", got, http.StatusTooManyRequests)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("expected registered code to be retryable")
+	}
+
+	err = NewError(CodeNotFound, "missing bar")
+	if got := HTTPStatus(err); got != http.StatusNotFound {
+		t.Errorf("expected default status to be kept but got %v", got)
+	}
+	if IsRetryable(err) {
+		t.Errorf("expected code to not be retryable")
+	}
+
+	info, ok := LookupCode(CodeNotFound)
+	if !ok || info.Description != "The resource does not exist." {
+		t.Errorf("unexpected code info %+v", info)
+	}
+	if _, ok := LookupCode("unknown"); ok {
+		t.Errorf("expected unknown code to not be registered")
+	}
+	if got, want := RegisteredCodes(), []string{CodeNotFound, "quota_exceeded"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestRegisterCodeNamespace(t *testing.T) {
+	RegisterCode("storage", CodeInfo{HTTPStatus: http.StatusServiceUnavailable, Retryable: true})
+	RegisterCode("storage.postgres.unique_violation", CodeInfo{HTTPStatus: http.StatusConflict})
+	t.Cleanup(func() {
+		delete(codeInfos, "storage")
+		delete(codeInfos, "storage.postgres.unique_violation")
+	})
+
+	err := NewError("storage.postgres.timeout", "statement timeout")
+	if got := HTTPStatus(err); got != http.StatusServiceUnavailable {
+		t.Errorf("\ngot:  %v\nwant: %v", got, http.StatusServiceUnavailable)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("expected code of registered namespace to be retryable")
+	}
+
+	err = NewError("storage.postgres.unique_violation", "duplicate key")
+	if got := HTTPStatus(err); got != http.StatusConflict {
+		t.Errorf("\ngot:  %v\nwant: %v", got, http.StatusConflict)
+	}
+	if IsRetryable(err) {
+		t.Errorf("expected registered code to override its namespace")
+	}
+
+	if got := HTTPStatus(NewError("storage_error", "unknown")); got != http.StatusInternalServerError {
+		t.Errorf("\ngot:  %v\nwant: %v", got, http.StatusInternalServerError)
+	}
+}
+
+func TestErrorCodeInfo(t *testing.T) {
+	RegisterCode("quota_exceeded", CodeInfo{
+		HTTPStatus: http.StatusTooManyRequests,
+		Severity:   SeverityWarning,
+		GRPCCode:   8, // codes.ResourceExhausted
+	})
+	t.Cleanup(func() { delete(codeInfos, "quota_exceeded") })
+
+	err := NewError("quota_exceeded", "tenant 12 made too many requests")
+	info, ok := ErrorCodeInfo(err)
+	if !ok || info.GRPCCode != 8 {
+		t.Errorf("unexpected code info %+v", info)
+	}
+
+	overridden := Attach(Wrap(err), CodeInfo{Severity: SeverityCritical, Retryable: true})
+	info, _ = ErrorCodeInfo(overridden)
+	want := CodeInfo{HTTPStatus: http.StatusTooManyRequests, Retryable: true, Severity: SeverityCritical, GRPCCode: 8}
+	if info != want {
+		t.Errorf("\ngot:  %+v\nwant: %+v", info, want)
+	}
+	if got := ErrorSeverity(overridden); got != SeverityCritical {
+		t.Errorf("\ngot:  %v\nwant: %v", got, SeverityCritical)
+	}
+	if got := ErrorSeverity(err); got != SeverityWarning {
+		t.Errorf("expected other errors with the code to keep the registered severity but got %v", got)
+	}
+	if !IsRetryable(overridden) || IsRetryable(err) {
+		t.Errorf("expected only the overridden error to be retryable")
+	}
+	if optedOut := Wrap(overridden).SetRetryable(false); IsRetryable(optedOut) || IsRetryable(Wrap(optedOut)) {
+		t.Errorf("expected SetRetryable(false) to take precedence over the code info")
+	}
+	decoded, decodeErr := Decode(Encode(Wrap(overridden).SetRetryable(false)))
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if IsRetryable(Attach(decoded, CodeInfo{Retryable: true})) {
+		t.Errorf("expected SetRetryable(false) to survive Encode")
+	}
+
+	if got := ErrorMessage(err); got != "" {
+		t.Errorf("expected no message but got %q", got)
+	}
+	withMessage := Attach(Wrap(err), CodeInfo{DefaultMessage: "Too many requests"})
+	if got, want := ErrorMessage(withMessage), "Too many requests"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := ErrorMessage(Wrap(withMessage).SetMessage("Slow down")), "Slow down"; got != want {
+		t.Errorf("expected set message to take precedence\ngot:  %q\nwant: %q", got, want)
+	}
+
+	if _, ok := ErrorCodeInfo(Foo()); ok {
+		t.Errorf("expected unregistered code to not have code info")
+	}
+	if got := HTTPStatus(Attach(Foo().(Error), CodeInfo{HTTPStatus: http.StatusConflict})); got != http.StatusConflict {
+		t.Errorf("expected attached code info to apply to unregistered codes but got %v", got)
+	}
+}
@@ -0,0 +1,122 @@
+package e
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Severity ranks how urgently an error needs attention. The zero value means
+// the severity is derived from the error by ErrorSeverity.
+type Severity int
+
+// Severities in increasing order of urgency.
+const (
+	SeverityInfo Severity = iota + 1
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// ErrorSeverity returns the severity of the ErrorCodeInfo of err. Otherwise it
+// is derived from the error: cancellations and client errors (HTTP status
+// below 500) are SeverityInfo, retryable errors are SeverityWarning and all
+// other errors are SeverityError.
+func ErrorSeverity(err error) Severity {
+	if info, ok := ErrorCodeInfo(err); ok && info.Severity != 0 {
+		return info.Severity
+	}
+	switch {
+	case IsCanceled(err), HTTPStatus(err) < http.StatusInternalServerError:
+		return SeverityInfo
+	case IsRetryable(err):
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// Reporter forwards errors which are important enough to page on to an
+// external system such as Sentry, a Slack webhook or a dead-letter queue.
+type Reporter interface {
+	Report(ctx context.Context, err error, severity Severity)
+}
+
+// ReporterFunc adapts a function to Reporter.
+type ReporterFunc func(ctx context.Context, err error, severity Severity)
+
+func (f ReporterFunc) Report(ctx context.Context, err error, severity Severity) {
+	f(ctx, err, severity)
+}
+
+type registeredReporter struct {
+	reporter    Reporter
+	minSeverity Severity
+}
+
+var (
+	reportersMu sync.Mutex
+	reporters   atomic.Value // []registeredReporter
+)
+
+// RegisterReporter registers r to receive the errors passed to Report which
+// have at least minSeverity. RegisterReporter is safe to call concurrently but
+// is intended to be called during initialization.
+//
+// Usage:
+//
+//	func init() {
+//		e.RegisterReporter(e.ReporterFunc(func(ctx context.Context, err error, s e.Severity) {
+//			sentry.CaptureException(err)
+//		}), e.SeverityError)
+//	}
+func RegisterReporter(r Reporter, minSeverity Severity) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+
+	regs, _ := reporters.Load().([]registeredReporter)
+	// copy so that concurrent readers never observe a partially updated slice
+	updated := make([]registeredReporter, len(regs), len(regs)+1)
+	copy(updated, regs)
+	reporters.Store(append(updated, registeredReporter{reporter: r, minSeverity: minSeverity}))
+}
+
+// Report forwards err with its ErrorSeverity to every registered Reporter
+// whose minimum severity it meets. Does nothing if err is nil or no Reporter
+// was registered.
+//
+// Reporters are called synchronously in the order they were registered and
+// should hand slow deliveries off to another goroutine.
+func Report(ctx context.Context, err error) {
+	regs, _ := reporters.Load().([]registeredReporter)
+	if err == nil || len(regs) == 0 {
+		return
+	}
+
+	severity := ErrorSeverity(err)
+	for _, reg := range regs {
+		if severity >= reg.minSeverity {
+			reg.reporter.Report(ctx, err, severity)
+		}
+	}
+}
+
+func resetReporters() {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	reporters.Store(([]registeredReporter)(nil))
+}
@@ -0,0 +1,58 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestErrorSeverity(t *testing.T) {
+	RegisterCode("payment_gateway_down", CodeInfo{Severity: SeverityCritical})
+	t.Cleanup(func() { delete(codeInfos, "payment_gateway_down") })
+
+	tests := []struct {
+		name string
+		err  error
+		want Severity
+	}{
+		{name: "registered", err: NewError("payment_gateway_down", "no response"), want: SeverityCritical},
+		{name: "client", err: NewError(CodeNotFound, "missing bar"), want: SeverityInfo},
+		{name: "canceled", err: Wrap(context.Canceled), want: SeverityInfo},
+		{name: "retryable", err: Foo().(Error).SetRetryable(true), want: SeverityWarning},
+		{name: "server", err: Foo(), want: SeverityError},
+		{name: "foreign", err: errors.New("boom"), want: SeverityError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorSeverity(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Cleanup(resetReporters)
+
+	// no-op without reporters
+	Report(context.Background(), Foo())
+
+	var pages, all []string
+	RegisterReporter(ReporterFunc(func(ctx context.Context, err error, s Severity) {
+		pages = append(pages, s.String()+" "+ErrorCode(err))
+	}), SeverityError)
+	RegisterReporter(ReporterFunc(func(ctx context.Context, err error, s Severity) {
+		all = append(all, s.String()+" "+ErrorCode(err))
+	}), SeverityInfo)
+
+	Report(context.Background(), Foo())
+	Report(context.Background(), NewError(CodeNotFound, "missing bar"))
+	Report(context.Background(), nil)
+
+	if got, want := len(pages), 1; got != want || pages[0] != "error database_error" {
+		t.Errorf("unexpected pages %q", pages)
+	}
+	if got, want := len(all), 2; got != want || all[1] != "info not_found" {
+		t.Errorf("unexpected reports %q", all)
+	}
+}
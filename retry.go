@@ -0,0 +1,125 @@
+package e
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// RetryOptions configures Retry. The zero value retries errors reported by
+// IsRetryable up to 3 times in total with exponential backoff.
+type RetryOptions struct {
+	// MaxAttempts limits how often fn is called. Defaults to 3.
+	MaxAttempts int
+
+	// Backoff is the delay before the second attempt. It doubles after every
+	// further attempt. A retry-after duration of the error takes precedence.
+	// Defaults to 100ms.
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between attempts computed from Backoff.
+	// 0 means no cap.
+	MaxBackoff time.Duration
+
+	// Retryable decides whether an error should be retried, e.g. based on its
+	// code. Defaults to IsRetryable.
+	Retryable func(error) bool
+
+	// KeepErrors joins the errors of every attempt into the final error like
+	// WrapAll instead of only keeping the last one. Use ErrorItems(err) to
+	// retrieve them.
+	KeepErrors bool
+}
+
+func (opts RetryOptions) withDefaults() RetryOptions {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 100 * time.Millisecond
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = IsRetryable
+	}
+	return opts
+}
+
+// Retry calls fn until it succeeds, returns an error which is not retryable,
+// or opts.MaxAttempts is reached. ctx ends the wait between attempts early.
+//
+// The final failure is wrapped with the name of the calling function and the
+// number of attempts, and keeps the code of the last error. Returns nil if fn
+// succeeded.
+//
+// Usage:
+//
+//	err := e.Retry(ctx, e.RetryOptions{MaxAttempts: 5}, func() error {
+//		return client.Send(ctx, msg)
+//	})
+//	// "Deliver: (after 5 attempts): Send: [unavailable] connection refused"
+func Retry(ctx context.Context, opts RetryOptions, fn func() error) Error {
+	opts = opts.withDefaults()
+
+	var (
+		attempts ItemErrors
+		last     error
+		stopped  error
+		attempt  int
+	)
+	delay := opts.Backoff
+	for attempt = 1; ; attempt++ {
+		last = fn()
+		if last == nil {
+			return nil
+		}
+		if opts.KeepErrors {
+			attempts = append(attempts, ItemError{Index: attempt - 1, Err: last})
+		}
+		if attempt == opts.MaxAttempts || !opts.Retryable(last) {
+			break
+		}
+
+		wait := delay
+		if d, ok := ErrorRetryAfter(last); ok {
+			wait = d
+		}
+		if stopped = sleep(ctx, wait); stopped != nil {
+			break
+		}
+		if delay *= 2; opts.MaxBackoff > 0 && delay > opts.MaxBackoff {
+			delay = opts.MaxBackoff
+		}
+	}
+
+	var wrapped errorImpl
+	if opts.KeepErrors {
+		wrapped = wrapImpl(getCallingFunc(2), last, &attempts)
+		// The attempts hide the code of the last error from ErrorCode.
+		if code := ErrorCode(last); code != "" {
+			wrapped.code = code
+		}
+	} else {
+		wrapped = wrapImpl(getCallingFunc(2), last, last)
+	}
+	wrapped.info = "after " + strconv.Itoa(attempt) + " attempt" // localizer.Ignore
+	if attempt > 1 {
+		wrapped.info += "s"
+	}
+	if stopped != nil {
+		wrapped.info += ": " + stopped.Error()
+	}
+	return hooks.run(wrapped)
+}
+
+// sleep waits for d unless ctx is done first, in which case it returns the
+// error of ctx.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	fast := RetryOptions{Backoff: time.Nanosecond}
+	unavailable := func() error {
+		return NewError(CodeUnavailable, "connection refused").SetRetryable(true)
+	}
+
+	tests := []struct {
+		name      string
+		opts      RetryOptions
+		errs      []error
+		wantCalls int
+		want      string
+	}{
+		{
+			name:      "succeeds after retries",
+			opts:      fast,
+			errs:      []error{unavailable(), unavailable(), nil},
+			wantCalls: 3,
+		},
+		{
+			name:      "gives up after max attempts",
+			opts:      fast,
+			errs:      []error{unavailable(), unavailable(), unavailable()},
+			wantCalls: 3,
+			want:      "TestRetry.func3: (after 3 attempts): TestRetry.func1: [unavailable] connection refused",
+		},
+		{
+			name:      "does not retry permanent errors",
+			opts:      fast,
+			errs:      []error{Foo()},
+			wantCalls: 1,
+			want:      "TestRetry.func3: (after 1 attempt): Foo: [database_error] cannot foo",
+		},
+		{
+			name: "retries codes chosen by policy",
+			opts: RetryOptions{
+				MaxAttempts: 2,
+				Backoff:     time.Nanosecond,
+				Retryable:   func(err error) bool { return ErrorCode(err) == CodeDatabase },
+			},
+			errs:      []error{Foo(), Foo()},
+			wantCalls: 2,
+			want:      "TestRetry.func3: (after 2 attempts): Foo: [database_error] cannot foo",
+		},
+		{
+			name:      "keeps errors",
+			opts:      RetryOptions{MaxAttempts: 2, Backoff: time.Nanosecond, KeepErrors: true},
+			errs:      []error{unavailable(), unavailable()},
+			wantCalls: 2,
+			want:      "TestRetry.func3: [unavailable] (after 2 attempts): item 0: TestRetry.func1: [unavailable] connection refused; item 1: TestRetry.func1: [unavailable] connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), tt.opts, func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("\ngot:  %d calls\nwant: %d calls", calls, tt.wantCalls)
+			}
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Retry(ctx, RetryOptions{Backoff: time.Hour}, func() error {
+		calls++
+		cancel()
+		return Wrap(errors.New("flaky")).SetRetryable(true)
+	})
+	if calls != 1 {
+		t.Errorf("expected no retries but got %d calls", calls)
+	}
+	want := "TestRetryContext: (after 1 attempt: context canceled): TestRetryContext.func1: flaky"
+	if got := err.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestRetryAfterHint(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := Retry(context.Background(), RetryOptions{Backoff: time.Hour}, func() error {
+		calls++
+		if calls == 1 {
+			return NewError(CodeRateLimited, "slow down").SetRetryAfter(time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("expected success after 2 calls but got %v after %d calls", err, calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Errorf("expected retry-after to take precedence over backoff but waited %v", elapsed)
+	}
+}
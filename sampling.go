@@ -0,0 +1,64 @@
+package e
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// unsampledStack is shared by errors whose diagnostics were skipped by a
+// sampling policy, so that errors wrapping them do not capture a stack either.
+var unsampledStack = &stack{}
+
+type sampler struct {
+	rate  uint64
+	count atomic.Uint64
+}
+
+var (
+	samplersMu sync.Mutex
+	samplers   atomic.Pointer[map[string]*sampler]
+)
+
+// SetSamplingPolicy makes NewError, Wrap and their variants capture the
+// stacktrace, timestamp and context fields of only 1 in rate errors with code,
+// starting with the first one. Errors wrapping an unsampled error do not
+// capture them either. This keeps some debugging signal for hot error paths
+// without paying for it on every occurrence. A rate of 1 or less removes the
+// policy for code.
+//
+// Usage:
+//
+//	func init() {
+//		e.SetSamplingPolicy(CodeCacheMiss, 1000)
+//	}
+func SetSamplingPolicy(code string, rate int) {
+	samplersMu.Lock()
+	defer samplersMu.Unlock()
+
+	updated := make(map[string]*sampler)
+	if m := samplers.Load(); m != nil {
+		for k, v := range *m {
+			updated[k] = v
+		}
+	}
+	if rate > 1 {
+		updated[code] = &sampler{rate: uint64(rate)}
+	} else {
+		delete(updated, code)
+	}
+	samplers.Store(&updated)
+}
+
+// sampled reports whether the diagnostics of a new error with code should be
+// captured.
+func sampled(code string) bool {
+	m := samplers.Load()
+	if m == nil {
+		return true
+	}
+	s, ok := (*m)[code]
+	if !ok {
+		return true
+	}
+	return (s.count.Add(1)-1)%s.rate == 0
+}
@@ -0,0 +1,68 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSetSamplingPolicy(t *testing.T) {
+	const code = "cache_miss"
+	SetSamplingPolicy(code, 3)
+	SetTimestamps(true)
+	t.Cleanup(func() {
+		SetSamplingPolicy(code, 1)
+		SetTimestamps(false)
+	})
+
+	var stacks, timestamps int
+	for i := 0; i < 6; i++ {
+		err := Wrap(NewError(code, "not cached"))
+		if ErrorStacktrace(err) != "" {
+			stacks++
+		}
+		if !ErrorTimestamp(err).IsZero() {
+			timestamps++
+		}
+	}
+	if stacks != 2 || timestamps != 2 {
+		t.Errorf("expected 2 of 6 errors to be sampled but got %d stacks and %d timestamps", stacks, timestamps)
+	}
+
+	if ErrorStacktrace(Foo()) == "" {
+		t.Errorf("expected errors with other codes to be sampled")
+	}
+
+	t.Run("wrapped foreign errors", func(t *testing.T) {
+		SetSamplingPolicy(CodeCanceled, 2)
+		t.Cleanup(func() { SetSamplingPolicy(CodeCanceled, 0) })
+
+		if err := Wrap(context.Canceled); ErrorStacktrace(err) == "" {
+			t.Errorf("expected first error to be sampled")
+		}
+		if err := Wrap(context.Canceled); ErrorStacktrace(err) != "" {
+			t.Errorf("expected second error to not be sampled")
+		}
+		if err := Wrap(errors.New("other")); ErrorStacktrace(err) == "" {
+			t.Errorf("expected errors with other codes to be sampled")
+		}
+	})
+
+	t.Run("context fields", func(t *testing.T) {
+		RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+			return map[string]interface{}{"request_id": "r1"}
+		})
+		t.Cleanup(func() { extractors = nil })
+
+		var fields int
+		for i := 0; i < 3; i++ {
+			if ErrorFields(NewCtx(context.Background(), code, "not cached")) != nil {
+				fields++
+			}
+		}
+		if fields != 1 {
+			t.Errorf("expected 1 of 3 errors to have fields but got %d", fields)
+		}
+	})
+
+}
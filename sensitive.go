@@ -0,0 +1,67 @@
+package e
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	sensitiveCodesMu sync.Mutex
+	sensitiveCodes   atomic.Pointer[map[string]struct{}]
+)
+
+// MarkSensitive makes ErrorMessage return an empty message for errors whose
+// ErrorCode is code, even if a call site set one, as a guard rail for
+// security-sensitive codes such as authorization failures whose messages
+// should never reach clients. A code marked for a namespace such as "auth"
+// also applies to hierarchical codes within it such as "auth.token.expired".
+// The DefaultMessage registered for the code with RegisterCode is returned
+// instead, and the policy set with SetMessagePolicy still applies.
+//
+// Usage:
+//
+//	func init() {
+//		e.MarkSensitive(CodePermission)
+//		e.SetMessagePolicy(func(code, msg string) string {
+//			if msg == "" && code == CodePermission {
+//				return "Access denied"
+//			}
+//			return msg
+//		})
+//	}
+func MarkSensitive(code string) {
+	sensitiveCodesMu.Lock()
+	defer sensitiveCodesMu.Unlock()
+
+	updated := map[string]struct{}{code: {}}
+	if m := sensitiveCodes.Load(); m != nil {
+		for k := range *m {
+			updated[k] = struct{}{}
+		}
+	}
+	sensitiveCodes.Store(&updated)
+}
+
+// isSensitive reports whether code or one of its namespaces was marked with
+// MarkSensitive.
+func isSensitive(code string) bool {
+	m := sensitiveCodes.Load()
+	if m == nil {
+		return false
+	}
+	for ; code != ""; code = parentCode(code) {
+		if _, ok := (*m)[code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// publicMessageOf returns the message of err unless its own code was marked
+// with MarkSensitive.
+func publicMessageOf(err error) string {
+	if isSensitive(codeOf(err)) {
+		return ""
+	}
+	return messageOf(err)
+}
@@ -0,0 +1,70 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarkSensitive(t *testing.T) {
+	t.Cleanup(func() { sensitiveCodes.Store(nil) })
+	MarkSensitive(CodePermission)
+	MarkSensitive("auth")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"sensitive code", NewError(CodePermission, "user 42 is not an admin").SetMessage("User 42 is not an admin"), ""},
+		{"wrapped", Wrap(NewError(CodePermission, "user 42 is not an admin").SetMessage("User 42 is not an admin")), ""},
+		{"namespace", NewError("auth.token.expired", "token expired").SetMessage("Token of user 42 expired"), ""},
+		{"other code", Wrap(Foo()).SetMessage("Bar is unavailable"), "Bar is unavailable"},
+		{"outer code", Wrap(NewError(CodePermission, "user 42 is not an admin")).SetCode(CodeNotFound).SetMessage("Bar does not exist"), "Bar does not exist"},
+		{"inner sensitive message", Wrap(NewError(CodePermission, "user 42 is not an admin").SetMessage("User 42 is not an admin")).SetCode(CodeNotFound), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorMessage(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("merged messages", func(t *testing.T) {
+		SetMessageMerging(true)
+		t.Cleanup(func() { SetMessageMerging(false) })
+		err := Wrap(NewError(CodePermission, "user 42 is not an admin").SetMessage("User 42 is not an admin")).
+			SetCode(CodeNotFound).
+			SetMessage("Bar does not exist")
+		if got, want := ErrorMessage(err), "Bar does not exist"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got, want := Messages(err), []string{"Bar does not exist"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("default message", func(t *testing.T) {
+		RegisterCode("auth", CodeInfo{DefaultMessage: "Authentication failed"})
+		t.Cleanup(func() { delete(codeInfos, "auth") })
+		err := NewError("auth.token.expired", "token expired").SetMessage("Token of user 42 expired")
+		if got, want := ErrorMessage(err), "Authentication failed"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		SetMessagePolicy(func(code, msg string) string {
+			if msg == "" && code == CodePermission {
+				return "Access denied"
+			}
+			return msg
+		})
+		t.Cleanup(func() { SetMessagePolicy(nil) })
+
+		err := NewError(CodePermission, "user 42 is not an admin").SetMessage("User 42 is not an admin")
+		if got, want := ErrorMessage(err), "Access denied"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+// Package slo classifies errors from package e by whether they count against
+// an availability objective, so that errors caused by clients do not burn the
+// error budget of a service.
+//
+// Usage:
+//
+//	var counter slo.Counter
+//
+//	func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//		err := h.serve(w, r)
+//		counter.Observe(err)
+//		...
+//	}
+package slo
+
+import (
+	"sync/atomic"
+
+	"github.com/kisunji/e"
+)
+
+// Class is the outcome of a request for availability calculations.
+type Class int
+
+const (
+	// Success is the class of requests which did not fail.
+	Success Class = iota
+	// ClientError is the class of requests which failed because of the
+	// client, such as invalid or canceled requests.
+	ClientError
+	// ServerError is the class of requests which failed because of the
+	// service. Only these count against an availability objective.
+	ServerError
+)
+
+func (c Class) String() string {
+	switch c {
+	case Success:
+		return "success"
+	case ClientError:
+		return "client_error"
+	case ServerError:
+		return "server_error"
+	}
+	return "unknown"
+}
+
+// Classify returns Success if err is nil and ClientError if err is canceled or
+// e.IsUserError(err). All other errors are ServerError. The class of an error
+// can therefore be set explicitly with its kind, and the class of a code can
+// be changed by registering its HTTP status with e.RegisterCode.
+func Classify(err error) Class {
+	switch {
+	case err == nil:
+		return Success
+	case e.IsCanceled(err), e.IsUserError(err):
+		return ClientError
+	}
+	return ServerError
+}
+
+// Impacting reports whether err counts against an availability objective.
+func Impacting(err error) bool {
+	return Classify(err) == ServerError
+}
+
+// Counts is a snapshot of a Counter.
+type Counts struct {
+	Success     uint64
+	ClientError uint64
+	ServerError uint64
+}
+
+// Total returns the number of observed requests.
+func (c Counts) Total() uint64 {
+	return c.Success + c.ClientError + c.ServerError
+}
+
+// Availability returns the ratio of requests which were not server errors, or
+// 1 if no requests were observed.
+func (c Counts) Availability() float64 {
+	if c.Total() == 0 {
+		return 1
+	}
+	return 1 - float64(c.ServerError)/float64(c.Total())
+}
+
+// Counter counts the outcomes of requests by Class. The zero value is ready to
+// use and is safe for concurrent use.
+type Counter struct {
+	// OnServerError is called with every observed error classified as
+	// ServerError, e.g. to increment an external metric. Must not be changed
+	// after the first call to Observe.
+	OnServerError func(err error)
+
+	counts [3]atomic.Uint64
+}
+
+// Observe counts the outcome of a request which returned err, which is nil if
+// the request succeeded, and returns its Class.
+func (c *Counter) Observe(err error) Class {
+	class := Classify(err)
+	c.counts[class].Add(1)
+	if class == ServerError && c.OnServerError != nil {
+		c.OnServerError(err)
+	}
+	return class
+}
+
+// Counts returns the number of observed requests of every Class.
+func (c *Counter) Counts() Counts {
+	return Counts{
+		Success:     c.counts[Success].Load(),
+		ClientError: c.counts[ClientError].Load(),
+		ServerError: c.counts[ServerError].Load(),
+	}
+}
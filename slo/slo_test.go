@@ -0,0 +1,64 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestClassify(t *testing.T) {
+	e.RegisterCode("slo_test.overloaded", e.CodeInfo{HTTPStatus: http.StatusServiceUnavailable})
+
+	tests := []struct {
+		name string
+		err  error
+		want Class
+	}{
+		{"nil", nil, Success},
+		{"not found", e.NewError(e.CodeNotFound, "cannot find bar"), ClientError},
+		{"validation", e.NewValidation().AddField("name", "is required"), ClientError},
+		{"canceled", e.Wrap(context.Canceled), ClientError},
+		{"client kind", e.NewError("bad_bar", "bar is malformed").SetKind(e.KindClient), ClientError},
+		{"internal", e.NewError("internal_error", "bar is nil"), ServerError},
+		{"timeout", e.Wrap(context.DeadlineExceeded), ServerError},
+		{"registered code", e.NewError("slo_test.overloaded", "too many bars"), ServerError},
+		{"foreign", errors.New("boom"), ServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+			if got, want := Impacting(tt.err), tt.want == ServerError; got != want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCounter(t *testing.T) {
+	var failures []error
+	counter := Counter{OnServerError: func(err error) { failures = append(failures, err) }}
+	if got := counter.Counts().Availability(); got != 1 {
+		t.Errorf("\ngot:  %v\nwant: %v", got, 1)
+	}
+
+	internal := e.NewError("internal_error", "bar is nil")
+	for _, err := range []error{nil, nil, e.NewError(e.CodeNotFound, "cannot find bar"), internal} {
+		counter.Observe(err)
+	}
+
+	want := Counts{Success: 2, ClientError: 1, ServerError: 1}
+	if got := counter.Counts(); got != want {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if got := counter.Counts().Availability(); got != 0.75 {
+		t.Errorf("\ngot:  %v\nwant: %v", got, 0.75)
+	}
+	if len(failures) != 1 || failures[0] != internal {
+		t.Errorf("expected OnServerError to be called with %v but got %v", internal, failures)
+	}
+}
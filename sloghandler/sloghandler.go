@@ -0,0 +1,145 @@
+// Package sloghandler expands errors from package e in log/slog records into
+// groups, using the same keys as package zaperr, so that existing logging
+// calls such as
+//
+//	logger.Error("cannot process bar", "error", err)
+//
+// produce structured errors without being changed.
+package sloghandler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/kisunji/e"
+)
+
+// Handler wraps another slog.Handler and replaces every attribute whose value
+// is an error containing an e.Error with a group of the error string, code,
+// kind, message, id, retryability, upstream, timestamp, ops, infos, fields
+// and stacktrace of the error. Other errors and attributes are passed through
+// unchanged.
+type Handler struct {
+	next slog.Handler
+}
+
+// New returns a Handler which passes expanded records to next.
+//
+// Usage:
+//
+//	logger := slog.New(sloghandler.New(slog.NewJSONHandler(os.Stderr, nil)))
+func New(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands the attributes of r and passes it to the wrapped handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(expand(attr))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+// WithAttrs expands attrs and returns a Handler wrapping the result of the
+// WithAttrs method of the wrapped handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		expanded[i] = expand(attr)
+	}
+	return &Handler{next: h.next.WithAttrs(expanded)}
+}
+
+// WithGroup returns a Handler wrapping the result of the WithGroup method of
+// the wrapped handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
+}
+
+// expand replaces attr with a group if its value is an error containing an
+// e.Error. Groups are expanded recursively.
+func expand(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, inner := range group {
+			expanded[i] = expand(inner)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny:
+		err, ok := attr.Value.Any().(error)
+		if !ok || err == nil {
+			return attr
+		}
+		var target e.Error
+		if !errors.As(err, &target) {
+			return attr
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(Attrs(err)...)}
+	default:
+		return attr
+	}
+}
+
+// Attrs returns the error string, code, kind, message, id, retryability,
+// upstream, timestamp, ops, infos, fields and stacktrace of err as attributes.
+// Keys of attributes which are not set are omitted. Returns nil if err is nil.
+func Attrs(err error) []slog.Attr {
+	if err == nil {
+		return nil
+	}
+
+	flat := e.Flatten(err)
+	attrs := []slog.Attr{slog.String("error", flat.Error)}
+	if flat.Code != "" {
+		attrs = append(attrs, slog.String("code", flat.Code))
+	}
+	if flat.Kind != "" {
+		attrs = append(attrs, slog.String("kind", string(flat.Kind)))
+	}
+	if flat.Message != "" {
+		attrs = append(attrs, slog.String("message", flat.Message))
+	}
+	if flat.ID != "" {
+		attrs = append(attrs, slog.String("id", flat.ID))
+	}
+	if flat.Retryable {
+		attrs = append(attrs, slog.Bool("retryable", true))
+	}
+	if flat.Upstream != nil {
+		upstream := []any{slog.String("system", flat.Upstream.System)}
+		if flat.Upstream.Endpoint != "" {
+			upstream = append(upstream, slog.String("endpoint", flat.Upstream.Endpoint))
+		}
+		if flat.Upstream.Status != 0 {
+			upstream = append(upstream, slog.Int("status", flat.Upstream.Status))
+		}
+		attrs = append(attrs, slog.Group("upstream", upstream...))
+	}
+	if !flat.Timestamp.IsZero() {
+		attrs = append(attrs, slog.Time("timestamp", flat.Timestamp))
+	}
+	if len(flat.Ops) > 0 {
+		attrs = append(attrs, slog.Any("ops", flat.Ops))
+	}
+	if len(flat.Infos) > 0 {
+		attrs = append(attrs, slog.Any("infos", flat.Infos))
+	}
+	if len(flat.Fields) > 0 {
+		attrs = append(attrs, slog.Any("fields", flat.Fields))
+	}
+	if flat.Stacktrace != "" {
+		attrs = append(attrs, slog.String("stacktrace", flat.Stacktrace))
+	}
+	return attrs
+}
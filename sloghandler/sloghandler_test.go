@@ -0,0 +1,70 @@
+package sloghandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	err := e.Wrap(e.NewError(e.CodeNotFound, "cannot find bar"), "bar id: 2hs8qh9").
+		SetMessage("Bar does not exist").
+		SetKind(e.KindClient).
+		SetField("bar_id", "2hs8qh9").
+		SetUpstream("bardb", "GET /bars", 404)
+	logger.With("cause", err).WithGroup("req").Error("request failed",
+		"err", err,
+		"plain", errors.New("EOF"),
+		slog.Group("nested", "err", err),
+	)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	expanded := map[string]interface{}{
+		"error":      "TestHandler: (bar id: 2hs8qh9): TestHandler: [not_found] cannot find bar",
+		"code":       e.CodeNotFound,
+		"kind":       string(e.KindClient),
+		"message":    "Bar does not exist",
+		"ops":        []interface{}{"TestHandler", "TestHandler"},
+		"infos":      []interface{}{"bar id: 2hs8qh9"},
+		"upstream":   map[string]interface{}{"system": "bardb", "endpoint": "GET /bars", "status": 404.0},
+		"fields":     map[string]interface{}{"bar_id": "2hs8qh9"},
+		"stacktrace": e.ErrorStacktrace(err),
+	}
+	want := map[string]interface{}{
+		"level": "ERROR",
+		"msg":   "request failed",
+		"cause": expanded,
+		"req": map[string]interface{}{
+			"err":    expanded,
+			"plain":  "EOF",
+			"nested": map[string]interface{}{"err": expanded},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestAttrsNil(t *testing.T) {
+	if got := Attrs(nil); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+}
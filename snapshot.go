@@ -0,0 +1,101 @@
+package e
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// Snapshot returns a detached copy of the chain of err which is safe to keep
+// after the objects referenced by the original errors are gone or reused,
+// e.g. to hand the error off to a job queue or another goroutine after the
+// request which caused it has finished. Errors not created by package e are
+// frozen into their text, code, message, retryability and fields, and lazy
+// infos are evaluated. The maps of fields are copied but their values are
+// not; use Encode to store errors outside of the process. Returns nil if err
+// is nil.
+//
+// Usage:
+//
+//	jobs <- Job{ID: id, Err: e.Snapshot(err)}
+func Snapshot(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	var layers []error
+	complete := walk(err, func(err error) bool {
+		layers = append(layers, err)
+		if _, ok := asImpl(err); ok {
+			return true
+		}
+		_, separated := ownText(err, errors.Unwrap(err))
+		return separated
+	})
+
+	var inner error
+	if !complete {
+		inner = &frozenError{text: "..."}
+	}
+	for i := len(layers) - 1; i >= 0; i-- {
+		inner = snapshotLayer(layers[i], inner)
+	}
+
+	if impl, ok := inner.(Error); ok {
+		return impl
+	}
+	return errorImpl{err: inner, stack: innerStack(err)}
+}
+
+// snapshotLayer returns a detached copy of a single error of a chain which
+// wraps inner instead of the errors it wrapped.
+func snapshotLayer(err, inner error) error {
+	switch x := err.(type) {
+	case ValidationError:
+		x.errorImpl = snapshotImpl(x.errorImpl, inner)
+		return x
+	case errorImpl:
+		return snapshotImpl(x, inner)
+	case *FieldErrors:
+		fieldErrors := slices.Clone(*x)
+		return &fieldErrors
+	case *ItemErrors:
+		items := make(ItemErrors, len(*x))
+		for i, item := range *x {
+			items[i] = ItemError{Index: item.Index, Err: Snapshot(item.Err)}
+		}
+		return &items
+	case *lazyError:
+		text := x.text.String()
+		return &lazyError{text: lazyText{fn: func() string { return text }}, err: inner}
+	}
+
+	text, separated := ownText(err, errors.Unwrap(err))
+	frozen := &frozenError{
+		text:    text,
+		code:    codeOf(err),
+		message: messageOf(err),
+	}
+	if separated {
+		frozen.err = inner
+	}
+	if r, ok := err.(Retrier); ok {
+		frozen.retryable = r.Retryable()
+	}
+	if hf, ok := err.(HasFields); ok {
+		frozen.fields = maps.Clone(hf.Fields())
+	}
+	return frozen
+}
+
+func snapshotImpl(impl errorImpl, inner error) errorImpl {
+	if impl.fields != nil {
+		impl.fields = fieldsRef(maps.Clone(*impl.fields))
+	}
+	if impl.details != nil {
+		details := slices.Clone(*impl.details)
+		impl.details = &details
+	}
+	impl.err = inner
+	return impl
+}
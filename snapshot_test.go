@@ -0,0 +1,86 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type mutableError struct {
+	text *string
+}
+
+func (m mutableError) Error() string { return *m.text }
+
+func TestSnapshot(t *testing.T) {
+	text := "connection reset"
+	err := WrapLazy(Wrap(fmt.Errorf("cannot dial: %w", Wrap(mutableError{text: &text})), "bar id: 2hs8qh9"), func() string {
+		return "lazy"
+	})
+	err = Wrapw(err, "attempt", 1).SetMessage("Try again later")
+	want := err.Error()
+
+	snapshot := Snapshot(err)
+	text = "reused"
+
+	if got := snapshot.Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := Ops(snapshot), Ops(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorMessage(snapshot); got != "Try again later" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "Try again later")
+	}
+	if got := ErrorFields(snapshot); !reflect.DeepEqual(got, map[string]interface{}{"attempt": 1}) {
+		t.Errorf("unexpected fields %v", got)
+	}
+	if got, want := ErrorStacktrace(snapshot), ErrorStacktrace(err); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		v := NewValidation().AddField("email", "must be valid")
+		snapshot := Snapshot(Wrap(v.Err()))
+		if got, want := ValidationErrors(snapshot), v.FieldErrors(); !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+		var target ValidationError
+		if !errors.As(snapshot, &target) {
+			t.Errorf("expected ValidationError in snapshot")
+		}
+	})
+
+	t.Run("items", func(t *testing.T) {
+		err := WrapAll([]error{Foo(), nil, NewError(CodeNotFound, "cannot find bar")})
+		snapshot := Snapshot(Wrap(err))
+		items := ErrorItems(snapshot)
+		if len(items) != 2 || items[0].Index != 0 || items[1].Index != 2 {
+			t.Fatalf("unexpected items %v", items)
+		}
+		if got := ErrorCode(items[1].Err); got != CodeNotFound {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeNotFound)
+		}
+		if got := len(Split(snapshot)); got != 2 {
+			t.Errorf("expected 2 errors but got %d", got)
+		}
+		if got, want := snapshot.Error(), Wrap(err).Error(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("foreign", func(t *testing.T) {
+		snapshot := Snapshot(fmt.Errorf("cannot dial: %w", Foo()))
+		if got, want := snapshot.Error(), "cannot dial: Foo: [database_error] cannot foo"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got := ErrorCode(snapshot); got != CodeDatabase {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeDatabase)
+		}
+	})
+
+	if got := Snapshot(nil); got != nil {
+		t.Errorf("expected nil but got %#v", got)
+	}
+}
@@ -0,0 +1,38 @@
+package e
+
+import "sync/atomic"
+
+var source atomic.Value // string
+
+// SetSource sets the name of the service or component, such as
+// "billing-service", which is stamped onto errors created by NewError and
+// friends and onto errors wrapped by Wrap which do not have a source yet. The
+// source is kept by Encode and WriteHTTP so that errors which cross service
+// boundaries still tell which service originated them. An empty name disables
+// stamping.
+//
+// Usage:
+//
+//	func init() {
+//		e.SetSource("billing-service")
+//	}
+func SetSource(name string) {
+	source.Store(name)
+}
+
+func currentSource() string {
+	name, _ := source.Load().(string)
+	return name
+}
+
+// Source returns the innermost source of an error in the chain of err, which
+// is the service that originated it. Otherwise returns an empty string.
+func Source(err error) string {
+	var name string
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok && impl.source != "" {
+			name = impl.source
+		}
+	}
+	return name
+}
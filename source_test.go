@@ -0,0 +1,57 @@
+package e
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	t.Cleanup(func() { SetSource("") })
+
+	if got := Source(Foo()); got != "" {
+		t.Errorf("expected no source but got %q", got)
+	}
+
+	SetSource("billing-service")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"new error", Foo()},
+		{"wrapped error", Wrap(errors.New("connection refused"))},
+		{"validation error", NewValidation().AddField("email", "must be valid")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := Source(tt.err), "billing-service"; got != want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, want)
+			}
+		})
+	}
+
+	t.Run("Encode", func(t *testing.T) {
+		encoded := Encode(Wrap(Foo()))
+		SetSource("api-gateway")
+		t.Cleanup(func() { SetSource("billing-service") })
+
+		decoded, err := Decode(encoded)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := Source(Wrap(decoded)), "billing-service"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, Wrap(Foo()))
+		SetSource("api-gateway")
+		t.Cleanup(func() { SetSource("billing-service") })
+
+		if got, want := Source(Wrap(FromHTTPResponse(rec.Result()))), "billing-service"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}
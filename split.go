@@ -0,0 +1,67 @@
+package e
+
+// Split returns the errors joined by the first error in the chain of err which
+// wraps multiple errors, such as an Error created by WrapAll, a Collector or a
+// Group, or an error created by errors.Join. Otherwise returns err itself as
+// the only element. Errors of WrapAll and friends are returned as ItemError so
+// that they keep their index. Returns nil if err is nil.
+//
+// Usage:
+//
+//	for _, err := range e.Split(err) {
+//		failures.WithLabelValues(e.ErrorCode(err)).Inc()
+//	}
+func Split(err error) []error {
+	if err == nil {
+		return nil
+	}
+	for err := range chain(err) {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			var errs []error
+			for _, err := range joined.Unwrap() {
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errs
+		}
+	}
+	return []error{err}
+}
+
+// Filter returns the errors of Split(err) for which keep returns true, e.g. to
+// retry only the retryable failures of a batch.
+//
+// Usage:
+//
+//	for _, err := range e.Filter(e.WrapAll(errs), e.IsRetryable) {
+//		retry(users[err.(e.ItemError).Index])
+//	}
+func Filter(err error, keep func(error) bool) []error {
+	var errs []error
+	for _, err := range Split(err) {
+		if keep(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// FilterCode returns the errors of Split(err) which have code, treating codes
+// registered with AliasCode like IsCode.
+func FilterCode(err error, code string) []error {
+	return Filter(err, func(err error) bool {
+		return IsCode(err, code)
+	})
+}
+
+// First returns the first error of Split(err) for which match returns true.
+// Otherwise returns nil.
+func First(err error, match func(error) bool) error {
+	for _, err := range Split(err) {
+		if match(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package e
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	invalid := NewError(CodeInvalid, "bad email")
+	unavailable := NewError(CodeUnavailable, "db down").SetRetryable(true)
+	tests := []struct {
+		name string
+		err  error
+		want []error
+	}{
+		{"nil", nil, nil},
+		{"single", invalid, []error{invalid}},
+		{"errors.Join", errors.Join(invalid, nil, unavailable), []error{invalid, unavailable}},
+		{"wrapped join", Wrap(errors.Join(invalid, unavailable)), []error{invalid, unavailable}},
+		{"WrapAll", WrapAll([]error{nil, invalid, unavailable}), []error{ItemError{Index: 1, Err: invalid}, ItemError{Index: 2, Err: unavailable}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	invalid := NewError(CodeInvalid, "bad email")
+	unavailable := NewError(CodeUnavailable, "db down").SetRetryable(true)
+	err := WrapAll([]error{invalid, unavailable, invalid})
+
+	if got, want := Filter(err, IsRetryable), []error{ItemError{Index: 1, Err: unavailable}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+	if got := FilterCode(err, CodeInvalid); len(got) != 2 || got[0].(ItemError).Index != 0 || got[1].(ItemError).Index != 2 {
+		t.Errorf("expected items 0 and 2 but got %v", got)
+	}
+	if got := FilterCode(err, CodeNotFound); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+	if got, want := First(err, IsRetryable), error(ItemError{Index: 1, Err: unavailable}); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+	if got := First(err, IsNotFound); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+}
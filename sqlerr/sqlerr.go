@@ -0,0 +1,127 @@
+// Package sqlerr translates driver-specific database errors into errors
+// which carry a canonical code and retryability compatible with package e.
+//
+// Postgres errors are recognized from any driver whose errors expose
+// SQLState() (lib/pq and pgx). MySQL errors are recognized from
+// github.com/go-sql-driver/mysql.
+package sqlerr
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/kisunji/e"
+)
+
+// Translate returns err wrapped with a canonical code and retryability if it
+// is recognized as a driver error. Otherwise err is returned unchanged.
+//
+// The result is meant to be wrapped at the call site so the op is recorded:
+//
+//	_, err := db.ExecContext(ctx, q, args...)
+//	if err != nil {
+//		return e.Wrap(sqlerr.Translate(err))
+//		// "Insert: [conflict] pq: duplicate key value violates unique constraint"
+//	}
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	code, retryable, ok := classify(err)
+	if !ok {
+		return err
+	}
+	return translated{code: code, retryable: retryable, err: err}
+}
+
+// translated implements e.ClientFacing and e.Retrier so it can be
+// introspected with e.ErrorCode and e.IsRetryable.
+type translated struct {
+	code      string
+	retryable bool
+	err       error
+}
+
+func (t translated) Error() string {
+	return "[" + t.code + "] " + t.err.Error() // localizer.Ignore
+}
+
+func (t translated) Unwrap() error {
+	return t.err
+}
+
+func (t translated) ClientCode() string {
+	return t.code
+}
+
+func (t translated) ClientMessage() string {
+	return ""
+}
+
+func (t translated) Retryable() bool {
+	return t.retryable
+}
+
+// sqlStater is implemented by *pq.Error and *pgconn.PgError.
+type sqlStater interface {
+	SQLState() string
+}
+
+func classify(err error) (code string, retryable bool, ok bool) {
+	var pgErr sqlStater
+	if errors.As(err, &pgErr) {
+		return classifySQLState(pgErr.SQLState())
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return classifyMySQL(myErr.Number)
+	}
+
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return e.CodeUnavailable, true, true
+	}
+	return "", false, false
+}
+
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+func classifySQLState(state string) (string, bool, bool) {
+	switch state {
+	case "23505": // unique_violation
+		return e.CodeConflict, false, true
+	case "23502", "23503", "23514": // not_null, foreign_key, check violations
+		return e.CodeInvalid, false, true
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return e.CodeConflict, true, true
+	case "55P03", "57014": // lock_not_available, query_canceled
+		return e.CodeTimeout, true, true
+	case "57P01", "57P02", "57P03", "53300": // shutdowns, too_many_connections
+		return e.CodeUnavailable, true, true
+	}
+	if strings.HasPrefix(state, "08") { // connection exceptions
+		return e.CodeUnavailable, true, true
+	}
+	return "", false, false
+}
+
+// See https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+func classifyMySQL(number uint16) (string, bool, bool) {
+	switch number {
+	case 1062, 1586: // ER_DUP_ENTRY, ER_DUP_ENTRY_WITH_KEY_NAME
+		return e.CodeConflict, false, true
+	case 1048, 1451, 1452, 3819: // ER_BAD_NULL_ERROR, foreign key, check violations
+		return e.CodeInvalid, false, true
+	case 1213: // ER_LOCK_DEADLOCK
+		return e.CodeConflict, true, true
+	case 1205: // ER_LOCK_WAIT_TIMEOUT
+		return e.CodeTimeout, true, true
+	case 1040, 1053, 2006, 2013: // too many connections, shutdown, gone away, lost connection
+		return e.CodeUnavailable, true, true
+	}
+	return "", false, false
+}
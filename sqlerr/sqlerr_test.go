@@ -0,0 +1,89 @@
+package sqlerr
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/kisunji/e"
+)
+
+type pgError struct {
+	state string
+}
+
+func (p *pgError) Error() string    { return "pg: " + p.state }
+func (p *pgError) SQLState() string { return p.state }
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantRetryable bool
+	}{
+		{
+			name:     "unrecognized error is unchanged",
+			err:      errors.New("boom"),
+			wantCode: "",
+		},
+		{
+			name:     "postgres unique violation",
+			err:      &pgError{state: "23505"},
+			wantCode: e.CodeConflict,
+		},
+		{
+			name:          "postgres serialization failure is retryable",
+			err:           &pgError{state: "40001"},
+			wantCode:      e.CodeConflict,
+			wantRetryable: true,
+		},
+		{
+			name:          "postgres connection exception is retryable",
+			err:           &pgError{state: "08006"},
+			wantCode:      e.CodeUnavailable,
+			wantRetryable: true,
+		},
+		{
+			name:     "mysql duplicate entry",
+			err:      &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"},
+			wantCode: e.CodeConflict,
+		},
+		{
+			name:          "mysql deadlock is retryable",
+			err:           fmt.Errorf("exec: %w", &mysql.MySQLError{Number: 1213}),
+			wantCode:      e.CodeConflict,
+			wantRetryable: true,
+		},
+		{
+			name:          "bad connection is retryable",
+			err:           driver.ErrBadConn,
+			wantCode:      e.CodeUnavailable,
+			wantRetryable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Translate(tt.err)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected translated error to wrap %v", tt.err)
+			}
+			if got := e.ErrorCode(err); got != tt.wantCode {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.wantCode)
+			}
+			if got := e.IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("\ngot retryable:  %v\nwant retryable: %v", got, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestTranslateWrapped(t *testing.T) {
+	err := e.Wrap(Translate(&pgError{state: "23505"}))
+	want := "TestTranslateWrapped: [conflict] pg: 23505"
+	if err.Error() != want {
+		t.Errorf("\ngot:  %q\nwant: %q", err, want)
+	}
+}
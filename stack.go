@@ -0,0 +1,190 @@
+package e
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames recorded in a stacktrace.
+const maxStackDepth = 32
+
+// stack is a stacktrace captured by NewError and Wrap. Only the program
+// counters are recorded when an error is created; they are formatted on the
+// first call to String so that errors which are never logged do not pay for
+// it. Errors in the same chain share the same stack.
+type stack struct {
+	once sync.Once
+	pcs  [maxStackDepth]uintptr
+	n    int
+	text string
+}
+
+// disabledStack is shared by errors created while stacktraces are disabled
+// with SetStacktraces. Unlike unsampledStack, it does not skip timestamps
+// and context fields.
+var disabledStack = &stack{}
+
+var (
+	omitStacktraces atomic.Bool
+
+	stackFiltersMu sync.Mutex
+	stackFilters   atomic.Pointer[[]string]
+	maxStackFrames atomic.Int64
+)
+
+// SetStacktraces enables capturing a stacktrace for the root of every error
+// chain, which is the default. Disabling stacktraces does not affect
+// timestamps and context fields.
+func SetStacktraces(enabled bool) {
+	omitStacktraces.Store(!enabled)
+}
+
+// AddStackFilter drops the frames of functions whose fully qualified name
+// starts with prefix, such as "runtime." or "github.com/acme/api/middleware.",
+// from formatted stacktraces, so that %+v and ErrorStacktrace focus on
+// application code. Stacktraces are formatted once when they are first used,
+// so filters should be added during initialization.
+//
+// Usage:
+//
+//	func init() {
+//		e.AddStackFilter("runtime.")
+//		e.AddStackFilter("net/http.")
+//	}
+func AddStackFilter(prefix string) {
+	stackFiltersMu.Lock()
+	defer stackFiltersMu.Unlock()
+
+	var updated []string
+	if filters := stackFilters.Load(); filters != nil {
+		updated = append(updated, *filters...)
+	}
+	updated = append(updated, prefix)
+	stackFilters.Store(&updated)
+}
+
+// SetMaxStackFrames limits formatted stacktraces to their first n frames
+// which are not dropped by AddStackFilter. Passing 0 or less restores the
+// default of all recorded frames, which are at most 32.
+func SetMaxStackFrames(n int) {
+	maxStackFrames.Store(int64(n))
+}
+
+// filteredFrame reports whether the frame of function is dropped by
+// AddStackFilter.
+func filteredFrame(function string) bool {
+	filters := stackFilters.Load()
+	if filters == nil {
+		return false
+	}
+	for _, prefix := range *filters {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// callers records the stack of the caller skip frames above the caller of
+// callers.
+func callers(skip int) *stack {
+	if omitStacktraces.Load() {
+		return disabledStack
+	}
+	s := &stack{}
+	// base offset is 2 to skip `runtime.Callers` and `callers` itself
+	s.n = runtime.Callers(skip+2, s.pcs[:])
+	return s
+}
+
+// Frame is a single frame of a stacktrace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// parseFrames returns the frames of a stacktrace formatted by String, which
+// also covers stacktraces decoded from other processes. Returns nil if text
+// is empty.
+func parseFrames(text string) []Frame {
+	var frames []Frame
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i := 0; i+1 < len(lines); i += 2 {
+		f := Frame{Function: strings.TrimSuffix(lines[i], "()")}
+		location := strings.TrimPrefix(lines[i+1], "\t")
+		if j := strings.LastIndex(location, ":"); j >= 0 {
+			f.Line, _ = strconv.Atoi(location[j+1:])
+			location = location[:j]
+		}
+		f.File = location
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// stackText returns a stack which was already formatted, e.g. by another
+// process.
+func stackText(text string) *stack {
+	if text == "" {
+		return nil
+	}
+	return &stack{text: text}
+}
+
+// String formats the stack with one function per frame followed by its
+// file and line on an indented line, applying AddStackFilter and
+// SetMaxStackFrames.
+func (s *stack) String() string {
+	if s == nil {
+		return ""
+	}
+	s.once.Do(func() {
+		if s.n == 0 {
+			return
+		}
+		limit := int(maxStackFrames.Load())
+		if limit <= 0 {
+			limit = maxStackDepth
+		}
+		var sb strings.Builder
+		frames := runtime.CallersFrames(s.pcs[:s.n])
+		for written, more := 0, true; more && written < limit; {
+			var frame runtime.Frame
+			frame, more = frames.Next()
+			if filteredFrame(frame.Function) {
+				continue
+			}
+			sb.WriteString(frame.Function)
+			sb.WriteString("()\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteString(":")
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteString("\n")
+			written++
+		}
+		s.text = sb.String()
+	})
+	return s.text
+}
+
+// innerStack returns the innermost stack of the chain of err so that it can
+// be shared by an error wrapping err.
+func innerStack(err error) *stack {
+	var inner *stack
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok {
+			if impl.stack != nil {
+				inner = impl.stack
+			}
+			continue
+		}
+		if hs, ok := err.(HasStacktrace); ok && hs.Stacktrace() != "" {
+			inner = stackText(hs.Stacktrace())
+		}
+	}
+	return inner
+}
@@ -0,0 +1,34 @@
+package e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStackFilters(t *testing.T) {
+	t.Cleanup(func() {
+		stackFilters.Store(nil)
+		SetMaxStackFrames(0)
+	})
+
+	unfiltered := ErrorStacktrace(Foo())
+	if !strings.Contains(unfiltered, "testing.tRunner()") {
+		t.Fatalf("expected frames of package testing but got %q", unfiltered)
+	}
+
+	AddStackFilter("testing.")
+	AddStackFilter("runtime.")
+	stack := ErrorStacktrace(Foo())
+	if strings.Contains(stack, "testing.") || strings.Contains(stack, "runtime.") {
+		t.Errorf("expected filtered frames to be dropped but got %q", stack)
+	}
+	if !strings.HasPrefix(stack, "github.com/kisunji/e.Foo()\n") {
+		t.Errorf("expected application frames to be kept but got %q", stack)
+	}
+
+	SetMaxStackFrames(1)
+	stack = ErrorStacktrace(Foo())
+	if got := strings.Count(stack, "\n\t"); got != 1 {
+		t.Errorf("expected 1 frame but got %q", stack)
+	}
+}
@@ -0,0 +1,93 @@
+package e
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultStoreCapacity is used by a MemoryStore without a Capacity.
+const DefaultStoreCapacity = 1000
+
+// Store persists the full details of errors server-side, so that only the id
+// and code of an error need to be returned to clients while support tooling
+// looks up the rest by id.
+type Store interface {
+	// Save stores err and returns the id to retrieve it with Get: the id of
+	// err if it has one, otherwise a new id which the stored error carries.
+	Save(ctx context.Context, err Error) (id string, saveErr error)
+
+	// Get returns the error saved with id. Returns an Error with CodeNotFound
+	// if there is none.
+	Get(ctx context.Context, id string) (Error, error)
+}
+
+// MemoryStore is a Store which keeps errors in memory, e.g. for tests and
+// single-instance services. Errors are kept as a Snapshot and the oldest
+// errors are evicted once Capacity is reached. The zero value is ready to use
+// and is safe for concurrent use.
+//
+// Usage:
+//
+//	var store e.MemoryStore
+//	...
+//	id, _ := store.Save(r.Context(), err)
+//	e.WriteHTTP(w, err.SetID(id))
+//
+//	// support tooling
+//	err, _ := store.Get(ctx, id)
+//	fmt.Printf("%+v\n", err)
+type MemoryStore struct {
+	// Capacity is the number of errors kept. Defaults to
+	// DefaultStoreCapacity.
+	Capacity int
+
+	mu    sync.Mutex
+	errs  map[string]Error
+	order []string
+}
+
+// Save implements Store. Ids are generated with the generator set with
+// SetIDGenerator, or with NewULID if there is none.
+func (s *MemoryStore) Save(_ context.Context, err Error) (string, error) {
+	if err == nil {
+		return "", NewError(CodeInvalid, "cannot save nil error")
+	}
+	id := ErrorID(err)
+	stored := Snapshot(err)
+	if id == "" {
+		if id = generateID(); id == "" {
+			id = NewULID()
+		}
+		stored = stored.SetID(id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.errs == nil {
+		s.errs = make(map[string]Error)
+	}
+	if _, ok := s.errs[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.errs[id] = stored
+
+	capacity := s.Capacity
+	if capacity <= 0 {
+		capacity = DefaultStoreCapacity
+	}
+	for len(s.order) > capacity {
+		delete(s.errs, s.order[0])
+		s.order = s.order[1:]
+	}
+	return id, nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, id string) (Error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err, ok := s.errs[id]; ok {
+		return err, nil
+	}
+	return nil, NewError(CodeNotFound, "no error saved with id "+id)
+}
@@ -0,0 +1,68 @@
+package e
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	var store MemoryStore
+
+	id, err := store.Save(ctx, Wrap(Foo()).SetField("bar_id", "2hs8qh9"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id == "" {
+		t.Fatalf("expected new id")
+	}
+	got, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "TestMemoryStore: Foo: [database_error] cannot foo"; got.Error() != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if ErrorID(got) != id {
+		t.Errorf("\ngot id:  %q\nwant id: %q", ErrorID(got), id)
+	}
+	if ErrorFields(got)["bar_id"] != "2hs8qh9" {
+		t.Errorf("expected fields to be kept but got %v", ErrorFields(got))
+	}
+
+	t.Run("existing id", func(t *testing.T) {
+		id, err := store.Save(ctx, Wrap(Foo()).SetID("2hs8qh9"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != "2hs8qh9" {
+			t.Errorf("\ngot:  %q\nwant: %q", id, "2hs8qh9")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if _, err := store.Get(ctx, "missing"); ErrorCode(err) != CodeNotFound {
+			t.Errorf("expected %s but got %v", CodeNotFound, err)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		if _, err := store.Save(ctx, nil); ErrorCode(err) != CodeInvalid {
+			t.Errorf("expected %s but got %v", CodeInvalid, err)
+		}
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		store := MemoryStore{Capacity: 2}
+		first, _ := store.Save(ctx, Foo().(Error))
+		for range 2 {
+			_, _ = store.Save(ctx, Bar().(Error))
+		}
+		if _, err := store.Get(ctx, first); ErrorCode(err) != CodeNotFound {
+			t.Errorf("expected oldest error to be evicted but got %v", err)
+		}
+		if n := len(store.errs); n != 2 {
+			t.Errorf("expected 2 errors but got %d", n)
+		}
+	})
+}
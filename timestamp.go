@@ -0,0 +1,56 @@
+package e
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var (
+	timestamps atomic.Bool
+	testClock  atomic.Pointer[func() time.Time]
+)
+
+// SetTimestamps enables recording the creation time of every Error created by
+// NewError and Wrap. Timestamps are disabled by default to avoid the overhead
+// of reading the clock. Use ErrorTimestamp and ErrorRootTimestamp to measure
+// how long an error took to propagate.
+func SetTimestamps(enabled bool) {
+	timestamps.Store(enabled)
+}
+
+// SetTestClock replaces the clock used for timestamps and by Deduper with fn,
+// e.g. a fixed time so that golden files of encoded errors do not change
+// between runs. Timestamps still need to be enabled with SetTimestamps.
+// Passing nil restores the system clock.
+//
+// Usage:
+//
+//	func TestMain(m *testing.M) {
+//		e.SetMode(e.ModeTest)
+//		e.SetTimestamps(true)
+//		e.SetTestClock(func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) })
+//		e.SetTestIDGenerator("err-")
+//		os.Exit(m.Run())
+//	}
+func SetTestClock(fn func() time.Time) {
+	if fn == nil {
+		testClock.Store(nil)
+		return
+	}
+	testClock.Store(&fn)
+}
+
+func now() time.Time {
+	if fn := testClock.Load(); fn != nil {
+		return (*fn)()
+	}
+	return time.Now()
+}
+
+// timestamp returns the current time if timestamps are enabled.
+func timestamp() time.Time {
+	if !timestamps.Load() {
+		return time.Time{}
+	}
+	return now()
+}
@@ -0,0 +1,44 @@
+package e
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestamps(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		err := Wrap(NewError(CodeInternal, "cannot foo"))
+		if ts := ErrorTimestamp(err); !ts.IsZero() {
+			t.Errorf("expected zero timestamp but got %v", ts)
+		}
+	})
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	wrapped := created.Add(time.Second)
+	clock := created
+	SetTimestamps(true)
+	SetTestClock(func() time.Time { return clock })
+	t.Cleanup(func() {
+		SetTimestamps(false)
+		SetTestClock(nil)
+	})
+
+	err := NewError(CodeInternal, "cannot foo")
+	clock = wrapped
+	err = Wrap(Wrap(Wrap(err)))
+
+	if got := ErrorTimestamp(err); !got.Equal(wrapped) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, wrapped)
+	}
+	if got := ErrorRootTimestamp(err); !got.Equal(created) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, created)
+	}
+
+	decoded, decodeErr := Decode(Encode(err))
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	if got := ErrorRootTimestamp(decoded); !got.Equal(created) {
+		t.Errorf("expected timestamp to survive Encode but got %v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ANSI escape codes used by RenderOptions.Color.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiYellow = "\x1b[33m"
+)
+
+// RenderOptions controls how FprintTree renders an error chain.
+type RenderOptions struct {
+	// Color highlights codes, dims ops and bolds messages with ANSI escape codes.
+	Color bool
+
+	// Fields prints the fields set on each error of the chain.
+	Fields bool
+}
+
+// FprintTree writes the error chain of err to w as an indented tree, one error
+// per line from outermost to innermost, without colors.
+//
+// Output:
+//
+//	ServeHTTP
+//	└─ (bar id: 2hs8qh9)
+//	   └─ Foo [database_error] "Please try again"
+//	      └─ cannot find bar
+func FprintTree(w io.Writer, err error) error {
+	return RenderOptions{}.FprintTree(w, err)
+}
+
+// FprintTree writes the error chain of err to w as an indented tree using opts.
+func (opts RenderOptions) FprintTree(w io.Writer, err error) error {
+	var sb strings.Builder
+	depth := 0
+	line := func(parts ...string) {
+		if depth > 0 {
+			sb.WriteString(strings.Repeat("   ", depth-1))
+			sb.WriteString("└─ ")
+		}
+		sb.WriteString(strings.Join(parts, " "))
+		sb.WriteString("\n")
+		depth++
+	}
+
+	for err := range chain(err) {
+		impl, ok := asImpl(err)
+		if !ok {
+			text, separated := ownText(err, errors.Unwrap(err))
+			if text = strings.TrimSuffix(text, ": "); text != "" {
+				line(redact(text))
+			}
+			if !separated {
+				break
+			}
+			continue
+		}
+
+		var parts []string
+		if impl.op != "" {
+			parts = append(parts, opts.style(ansiDim, impl.op))
+		}
+		if impl.code != "" {
+			parts = append(parts, opts.style(ansiYellow, "["+impl.code+"]"))
+		}
+		if impl.message != "" {
+			parts = append(parts, opts.style(ansiBold, fmt.Sprintf("%q", redact(impl.message))))
+		}
+		if opts.Fields {
+			parts = append(parts, renderFields(impl.Fields())...)
+		}
+		if len(parts) > 0 {
+			line(parts...)
+		}
+		if impl.info != "" {
+			line("(" + redact(impl.info) + ")")
+		}
+	}
+
+	_, writeErr := io.WriteString(w, sb.String())
+	return writeErr
+}
+
+func (opts RenderOptions) style(ansi, s string) string {
+	if !opts.Color {
+		return s
+	}
+	return ansi + s + ansiReset
+}
+
+func renderFields(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rendered := make([]string, 0, len(keys))
+	for _, k := range keys {
+		rendered = append(rendered, k+"="+redact(fmt.Sprint(fields[k])))
+	}
+	return rendered
+}
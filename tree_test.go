@@ -0,0 +1,38 @@
+package e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFprintTree(t *testing.T) {
+	t.Run("renders chain", func(t *testing.T) {
+		var sb strings.Builder
+		err := Wrap(FizzBuzz(), "bar id: 2hs8qh9").SetMessage("Please try again")
+		if writeErr := FprintTree(&sb, err); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+		want := `TestFprintTree.func1 "Please try again"
+└─ (bar id: 2hs8qh9)
+   └─ FizzBuzz
+      └─ not encouraged but compatible
+         └─ Foo [database_error]
+            └─ cannot foo
+`
+		if sb.String() != want {
+			t.Errorf("\ngot:\n%s\nwant:\n%s", sb.String(), want)
+		}
+	})
+	t.Run("renders fields and colors", func(t *testing.T) {
+		var sb strings.Builder
+		err := NewError(CodeDatabase, "cannot foo").SetField("id", 1)
+		opts := RenderOptions{Color: true, Fields: true}
+		if writeErr := opts.FprintTree(&sb, err); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+		want := "\x1b[2mTestFprintTree.func2\x1b[0m \x1b[33m[database_error]\x1b[0m id=1\n└─ cannot foo\n"
+		if sb.String() != want {
+			t.Errorf("\ngot:  %q\nwant: %q", sb.String(), want)
+		}
+	})
+}
@@ -0,0 +1,7 @@
+module github.com/kisunji/e/twirperr
+
+go 1.24
+
+require github.com/kisunji/e v0.0.0-00010101000000-000000000000
+
+replace github.com/kisunji/e => ../
@@ -0,0 +1,158 @@
+// Package twirperr maps errors from package e to and from Twirp errors. The
+// Twirp code is mapped from the canonical codes and the original code, id and
+// retryability are carried in error metadata so they round-trip.
+//
+// The package works with the Twirp wire representation (code, message and
+// metadata) so it does not depend on github.com/twitchtv/twirp:
+//
+//	// server
+//	tw := twirperr.ToTwirp(err)
+//	twerr := twirp.NewError(twirp.ErrorCode(tw.Code), tw.Msg)
+//	for k, v := range tw.Meta {
+//		twerr = twerr.WithMeta(k, v)
+//	}
+//
+//	// client
+//	if twerr, ok := err.(twirp.Error); ok {
+//		err = twirperr.FromTwirp(string(twerr.Code()), twerr.Msg(), twerr.MetaMap())
+//	}
+package twirperr
+
+import (
+	"github.com/kisunji/e"
+)
+
+// Metadata keys used to carry the error.
+const (
+	MetaCode      = "err_code"
+	MetaID        = "err_id"
+	MetaRetryable = "err_retryable"
+)
+
+// Twirp error codes, see https://twitchtv.github.io/twirp/docs/spec_v7.html#error-codes
+var toTwirp = map[string]string{
+	e.CodeNotFound:    "not_found",
+	e.CodeInvalid:     "invalid_argument",
+	e.CodeValidation:  "invalid_argument",
+	e.CodePermission:  "permission_denied",
+	e.CodeTimeout:     "deadline_exceeded",
+	e.CodeCanceled:    "canceled",
+	e.CodeConflict:    "aborted",
+	e.CodeUnavailable: "unavailable",
+	e.CodeRateLimited: "resource_exhausted",
+}
+
+var fromTwirp = map[string]string{
+	"not_found":          e.CodeNotFound,
+	"invalid_argument":   e.CodeInvalid,
+	"malformed":          e.CodeInvalid,
+	"permission_denied":  e.CodePermission,
+	"unauthenticated":    e.CodePermission,
+	"deadline_exceeded":  e.CodeTimeout,
+	"canceled":           e.CodeCanceled,
+	"aborted":            e.CodeConflict,
+	"already_exists":     e.CodeConflict,
+	"unavailable":        e.CodeUnavailable,
+	"resource_exhausted": e.CodeRateLimited,
+}
+
+// Error is the Twirp wire representation of an error, as written in the JSON
+// body of Twirp error responses.
+type Error struct {
+	Code string            `json:"code"`
+	Msg  string            `json:"msg"`
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// ToTwirp converts err into the Twirp wire representation. Only the
+// client-facing parts of err are sent: the message is e.ErrorMessage(err).
+// Returns nil if err is nil.
+func ToTwirp(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	converted := &Error{Code: Code(err), Msg: e.ErrorMessage(err)}
+	if meta := Meta(err); len(meta) > 0 {
+		converted.Meta = meta
+	}
+	return converted
+}
+
+// Code returns the Twirp error code for err. Returns "internal" for unknown
+// codes and an empty string if err is nil.
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+	if code, ok := toTwirp[e.ErrorCode(err)]; ok {
+		return code
+	}
+	return "internal"
+}
+
+// Meta returns the metadata which carries the code, id and retryability of err.
+func Meta(err error) map[string]string {
+	meta := make(map[string]string)
+	if code := e.ErrorCode(err); code != "" {
+		meta[MetaCode] = code
+	}
+	if id := e.ErrorID(err); id != "" {
+		meta[MetaID] = id
+	}
+	if e.IsRetryable(err) {
+		meta[MetaRetryable] = "true"
+	}
+	return meta
+}
+
+// FromTwirp reconstructs an error compatible with package e from the code,
+// message and metadata of a Twirp error. The code is taken from metadata set
+// by Meta, or mapped from the Twirp code onto a canonical code otherwise.
+func FromTwirp(twirpCode, msg string, meta map[string]string) error {
+	remote := remoteError{
+		code:      fromTwirp[twirpCode],
+		twirpCode: twirpCode,
+		message:   msg,
+		id:        meta[MetaID],
+		retryable: meta[MetaRetryable] == "true",
+	}
+	if code := meta[MetaCode]; code != "" {
+		remote.code = code
+	}
+	return remote
+}
+
+// remoteError implements e.ClientFacing, e.Retrier and e.HasID so it can be
+// introspected with e.ErrorCode, e.ErrorMessage, e.IsRetryable and e.ErrorID.
+type remoteError struct {
+	code      string
+	twirpCode string
+	message   string
+	id        string
+	retryable bool
+}
+
+func (r remoteError) Error() string {
+	text := "twirp error " + r.twirpCode + ": " + r.message // localizer.Ignore
+	if r.code == "" {
+		return text
+	}
+	return "[" + r.code + "] " + text // localizer.Ignore
+}
+
+func (r remoteError) ClientCode() string {
+	return r.code
+}
+
+func (r remoteError) ClientMessage() string {
+	return r.message
+}
+
+func (r remoteError) Retryable() bool {
+	return r.retryable
+}
+
+func (r remoteError) ID() string {
+	return r.id
+}
@@ -0,0 +1,67 @@
+package twirperr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestRoundTrip(t *testing.T) {
+	err := e.NewError("quota_exceeded", "too many bars for tenant 12").
+		SetMessage("Please slow down").
+		SetID("abc123").
+		SetRetryable(true)
+
+	if got := Code(err); got != "internal" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "internal")
+	}
+
+	received := FromTwirp(Code(err), e.ErrorMessage(err), Meta(err))
+	if got := e.ErrorCode(received); got != "quota_exceeded" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "quota_exceeded")
+	}
+	if got := e.ErrorMessage(received); got != "Please slow down" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "Please slow down")
+	}
+	if got := e.ErrorID(received); got != "abc123" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "abc123")
+	}
+	if !e.IsRetryable(received) {
+		t.Errorf("expected received error to be retryable")
+	}
+	if got, want := received.Error(), "[quota_exceeded] twirp error internal: Please slow down"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestCanonicalCodes(t *testing.T) {
+	if got := Code(e.NewError(e.CodeNotFound, "no bar")); got != "not_found" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "not_found")
+	}
+	if got := e.ErrorCode(FromTwirp("unavailable", "down", nil)); got != e.CodeUnavailable {
+		t.Errorf("\ngot:  %q\nwant: %q", got, e.CodeUnavailable)
+	}
+}
+
+func TestToTwirp(t *testing.T) {
+	err := e.NewError(e.CodeNotFound, "no bar for tenant 12").SetMessage("Bar does not exist").SetID("abc123")
+
+	sent := ToTwirp(err)
+	data, marshalErr := json.Marshal(sent)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	want := `{"code":"not_found","msg":"Bar does not exist","meta":{"err_code":"not_found","err_id":"abc123"}}`
+	if got := string(data); got != want {
+		t.Errorf("\ngot:  %s\nwant: %s", got, want)
+	}
+
+	received := FromTwirp(sent.Code, sent.Msg, sent.Meta)
+	if got := e.ErrorID(received); got != "abc123" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "abc123")
+	}
+	if got := ToTwirp(nil); got != nil {
+		t.Errorf("expected nil but got %#v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Code marks a string passed to E as the code of the error.
+type Code string
+
+// E constructs an Error from arguments in any order, dispatching on their
+// type like the E function of upspin-style error packages. It is meant to ease
+// migrating from such packages; NewError and Wrap are preferred otherwise.
+//
+//	Code                    the code of the error
+//	Kind                    the kind of the error
+//	error                   the error to wrap, ignored if nil
+//	map[string]interface{}  fields of the error
+//	string                  the cause of a new error, or the info if an
+//	                        error is wrapped
+//
+// Arguments of other types are formatted with fmt and treated like strings,
+// which are joined with spaces. The op is derived from the calling function
+// like in NewError and Wrap, so op arguments of upspin-style calls should be
+// removed.
+//
+// Usage:
+//
+//	return e.E(e.Code(CodeNotFound), e.KindClient, "cannot find bar", err)
+func E(args ...interface{}) Error {
+	var (
+		code   string
+		kind   Kind
+		err    error
+		fields map[string]interface{}
+		text   []string
+	)
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case Code:
+			code = string(arg)
+		case Kind:
+			kind = arg
+		case error:
+			err = arg
+		case map[string]interface{}:
+			if fields == nil {
+				fields = make(map[string]interface{}, len(arg))
+			}
+			for k, v := range arg {
+				fields[k] = v
+			}
+		case string:
+			text = append(text, arg)
+		case nil:
+		default:
+			text = append(text, fmt.Sprint(arg))
+		}
+	}
+
+	if err != nil {
+		return wrap(err, strings.Join(text, " "), func(wrapped errorImpl) Error {
+			if code != "" {
+				wrapped.code = code
+			}
+			wrapped.kind = kind
+			wrapped.fields = fieldsRef(fields)
+			return wrapped
+		})
+	}
+	created := newImpl(getCallingFunc(2), code, errors.New(strings.Join(text, " ")))
+	created.kind = kind
+	created.fields = fieldsRef(fields)
+	return runNewHooks(created)
+}
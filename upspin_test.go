@@ -0,0 +1,39 @@
+package e
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestE(t *testing.T) {
+	t.Run("new error", func(t *testing.T) {
+		err := E("cannot find bar", KindClient, Code(CodeNotFound), map[string]interface{}{"bar_id": 7})
+		if got, want := err.Error(), "TestE.func1: [not_found] cannot find bar"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if got := ErrorKind(err); got != KindClient {
+			t.Errorf("\ngot:  %q\nwant: %q", got, KindClient)
+		}
+		if got, want := ErrorFields(err), map[string]interface{}{"bar_id": 7}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+	})
+	t.Run("wraps error", func(t *testing.T) {
+		cause := Foo()
+		err := E(cause, "bar id:", 7, Code(CodeInvalid))
+		if got, want := err.Error(), "TestE.func2: [invalid] (bar id: 7): Foo: [database_error] cannot foo"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+		if !errors.Is(err, cause) {
+			t.Errorf("expected wrapped error to be in the chain")
+		}
+	})
+	t.Run("nil error is ignored", func(t *testing.T) {
+		var cause error
+		err := E(cause, "cannot find bar")
+		if got, want := err.Error(), "TestE.func3: cannot find bar"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package e
+
+// UpstreamInfo describes the external dependency whose failure caused an
+// error, as set with SetUpstream.
+type UpstreamInfo struct {
+	// System names the dependency, such as "stripe" or "postgres".
+	System string `json:"system"`
+	// Endpoint is the operation or address of the dependency which failed,
+	// such as "POST /v1/charges".
+	Endpoint string `json:"endpoint,omitempty"`
+	// Status is the status code returned by the dependency, or 0 if it did
+	// not respond.
+	Status int `json:"status,omitempty"`
+}
+
+// Upstream returns the outermost upstream set with SetUpstream in the chain
+// of err. Otherwise returns false.
+//
+// Usage:
+//
+//	if upstream, ok := e.Upstream(err); ok {
+//		dependencyFailures.WithLabelValues(upstream.System).Inc()
+//	}
+func Upstream(err error) (UpstreamInfo, bool) {
+	for err := range chain(err) {
+		if impl, ok := asImpl(err); ok && impl.upstream != nil {
+			return *impl.upstream, true
+		}
+	}
+	return UpstreamInfo{}, false
+}
+
+func (e errorImpl) SetUpstream(system, endpoint string, status int) Error {
+	e.upstream = &UpstreamInfo{System: system, Endpoint: endpoint, Status: status}
+	return e
+}
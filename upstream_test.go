@@ -0,0 +1,49 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpstream(t *testing.T) {
+	stripe := UpstreamInfo{System: "stripe", Endpoint: "POST /v1/charges", Status: 503}
+	tests := []struct {
+		name   string
+		err    error
+		want   UpstreamInfo
+		wantOk bool
+	}{
+		{"nil", nil, UpstreamInfo{}, false},
+		{"unset", Foo(), UpstreamInfo{}, false},
+		{"set", NewError(CodeUnavailable, "cannot charge").SetUpstream("stripe", "POST /v1/charges", 503), stripe, true},
+		{"wrapped", Wrap(Wrap(Foo()).SetUpstream("stripe", "POST /v1/charges", 503)), stripe, true},
+		{"outermost wins", Wrap(Wrap(Foo()).SetUpstream("postgres", "", 0)).SetUpstream("stripe", "POST /v1/charges", 503), stripe, true},
+		{"validation error", NewValidation().SetUpstream("stripe", "POST /v1/charges", 503), stripe, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Upstream(tt.err)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("\ngot:  %+v, %v\nwant: %+v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	t.Run("Encode", func(t *testing.T) {
+		decoded, err := Decode(Encode(Wrap(Wrap(Foo()).SetUpstream("stripe", "POST /v1/charges", 503))))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, _ := Upstream(decoded); got != stripe {
+			t.Errorf("\ngot:  %+v\nwant: %+v", got, stripe)
+		}
+	})
+
+	t.Run("ToMap", func(t *testing.T) {
+		got := ToMap(Wrap(Foo()).SetUpstream("stripe", "", 503))["upstream"]
+		want := map[string]interface{}{"system": "stripe", "status": 503}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package e
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// CodeValidation is the code of errors created by NewValidation.
+const CodeValidation = "validation_error"
+
+// FieldError describes why a single input field is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is the cause of a ValidationError.
+type FieldErrors []FieldError
+
+func (f FieldErrors) Error() string {
+	if len(f) == 0 {
+		return "validation failed"
+	}
+	var sb strings.Builder
+	for i, fe := range f {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(fe.Field)
+		sb.WriteString(": ")
+		sb.WriteString(fe.Message)
+	}
+	return sb.String()
+}
+
+// ValidationError is an Error with code CodeValidation which collects
+// per-field errors. Use ValidationErrors(err) to retrieve them from an error
+// chain.
+//
+// The cause of a ValidationError is a *FieldErrors so that it stays
+// comparable for errors.Is.
+type ValidationError struct {
+	errorImpl
+}
+
+// NewValidation constructs a new ValidationError. Fields are added with
+// AddField.
+//
+// Usage:
+//
+//	func Validate(u User) error {
+//		v := e.NewValidation()
+//		if !strings.Contains(u.Email, "@") {
+//			v = v.AddField("email", "must be valid")
+//		}
+//		if u.Age < 0 {
+//			v = v.AddField("age", "must be positive")
+//		}
+//		return v.Err()
+//	}
+func NewValidation() ValidationError {
+	v := ValidationError{
+		errorImpl: newImpl(getCallingFunc(2), CodeValidation, &FieldErrors{}),
+	}
+	if hooked, ok := runNewHooks(v).(ValidationError); ok {
+		return hooked
+	}
+	return v
+}
+
+// AddField returns a copy of v with an additional field error.
+func (v ValidationError) AddField(field, message string) ValidationError {
+	prev := v.FieldErrors()
+	fieldErrors := make(FieldErrors, len(prev), len(prev)+1)
+	copy(fieldErrors, prev)
+	fieldErrors = append(fieldErrors, FieldError{Field: field, Message: message})
+	v.err = &fieldErrors
+	return v
+}
+
+// FieldErrors returns the field errors added to v.
+func (v ValidationError) FieldErrors() FieldErrors {
+	if fieldErrors, ok := v.err.(*FieldErrors); ok {
+		return *fieldErrors
+	}
+	return nil
+}
+
+// Err returns v if any field errors were added. Otherwise returns nil.
+func (v ValidationError) Err() error {
+	if len(v.FieldErrors()) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v ValidationError) SetCode(code string) Error {
+	v.errorImpl = v.errorImpl.SetCode(code).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetKind(kind Kind) Error {
+	v.errorImpl = v.errorImpl.SetKind(kind).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetMessage(message string) Error {
+	v.errorImpl = v.errorImpl.SetMessage(message).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetHint(hint string) Error {
+	v.errorImpl = v.errorImpl.SetHint(hint).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetRetryable(retryable bool) Error {
+	v.errorImpl = v.errorImpl.SetRetryable(retryable).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetRetryAfter(d time.Duration) Error {
+	v.errorImpl = v.errorImpl.SetRetryAfter(d).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetField(key string, value interface{}) Error {
+	v.errorImpl = v.errorImpl.SetField(key, value).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetTimeout(timeout bool) Error {
+	v.errorImpl = v.errorImpl.SetTimeout(timeout).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetTemporary(temporary bool) Error {
+	v.errorImpl = v.errorImpl.SetTemporary(temporary).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetID(id string) Error {
+	v.errorImpl = v.errorImpl.SetID(id).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetDetailJSON(name string, detail json.RawMessage) Error {
+	v.errorImpl = v.errorImpl.SetDetailJSON(name, detail).(errorImpl)
+	return v
+}
+
+func (v ValidationError) SetUpstream(system, endpoint string, status int) Error {
+	v.errorImpl = v.errorImpl.SetUpstream(system, endpoint, status).(errorImpl)
+	return v
+}
+
+func (v ValidationError) With(optionalInfo ...string) Error {
+	return wrap(v, firstInfo(optionalInfo), nil)
+}
+
+// MarshalJSON encodes v with its code, message and a fields array.
+func (v ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(httpBody{
+		Code:    v.code,
+		Message: v.message,
+		ID:      v.id,
+		Fields:  v.FieldErrors(),
+	})
+}
+
+// ValidationErrors returns the field errors of the first ValidationError in
+// the chain. Otherwise returns nil.
+func ValidationErrors(err error) FieldErrors {
+	var fieldErrors *FieldErrors
+	if errors.As(err, &fieldErrors) {
+		return *fieldErrors
+	}
+	return nil
+}
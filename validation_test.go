@@ -0,0 +1,86 @@
+package e
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func validateUser(email string, age int) error {
+	v := NewValidation()
+	if email == "" {
+		v = v.AddField("email", "must be valid")
+	}
+	if age < 0 {
+		v = v.AddField("age", "must be positive")
+	}
+	return v.Err()
+}
+
+func TestValidation(t *testing.T) {
+	t.Run("no field errors returns nil", func(t *testing.T) {
+		if err := validateUser("a@b.c", 1); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+	t.Run("satisfies Error", func(t *testing.T) {
+		err := Wrap(validateUser("", -1))
+		want := "TestValidation.func2: validateUser: [validation_error] email: must be valid; age: must be positive"
+		if err.Error() != want {
+			t.Errorf("\ngot:  %q\nwant: %q", err, want)
+		}
+		if got := ErrorCode(err); got != CodeValidation {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeValidation)
+		}
+		if got, want := Ops(err), []string{"TestValidation.func2", "validateUser"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("setters keep field errors", func(t *testing.T) {
+		err := NewValidation().AddField("email", "must be valid").SetMessage("Please fix the form")
+		if got := len(ValidationErrors(err)); got != 1 {
+			t.Errorf("expected 1 field error but got %d", got)
+		}
+		if got := ErrorMessage(err); got != "Please fix the form" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "Please fix the form")
+		}
+	})
+	t.Run("AddField does not modify original", func(t *testing.T) {
+		v := NewValidation().AddField("email", "must be valid")
+		_ = v.AddField("age", "must be positive")
+		if got := len(v.FieldErrors()); got != 1 {
+			t.Errorf("expected 1 field error but got %d", got)
+		}
+	})
+	t.Run("works with errors.Is", func(t *testing.T) {
+		err := validateUser("", -1)
+		if !errors.Is(Wrap(err), err) {
+			t.Errorf("expected errors.Is to match")
+		}
+	})
+	t.Run("marshals fields array", func(t *testing.T) {
+		b, err := json.Marshal(NewValidation().AddField("email", "must be valid"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"code":"validation_error","fields":[{"field":"email","message":"must be valid"}]}`
+		if string(b) != want {
+			t.Errorf("\ngot:  %s\nwant: %s", b, want)
+		}
+	})
+	t.Run("round trips through HTTP", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, validateUser("", -1))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("\ngot:  %d\nwant: %d", rec.Code, http.StatusBadRequest)
+		}
+		got := ValidationErrors(FromHTTPResponse(rec.Result()))
+		want := FieldErrors{{"email", "must be valid"}, {"age", "must be positive"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+	})
+}
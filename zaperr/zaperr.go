@@ -0,0 +1,114 @@
+// Package zaperr logs errors from package e as structured zap fields instead of
+// the flat string produced by zap.Error.
+//
+// Package e does not depend on zap, so errors are adapted with Object rather
+// than implementing zapcore.ObjectMarshaler themselves.
+package zaperr
+
+import (
+	"github.com/kisunji/e"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Error returns a zap field with key "error" containing the error string,
+// code, kind, message, id, retryability, upstream, timestamp, ops, infos,
+// fields and stacktrace of err.
+//
+// Usage:
+//
+//	logger.Error("cannot process bar", zaperr.Error(err))
+func Error(err error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError behaves like Error with a custom key.
+func NamedError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Object(err))
+}
+
+// Object adapts err to zapcore.ObjectMarshaler.
+func Object(err error) zapcore.ObjectMarshaler {
+	return object{err: err}
+}
+
+type object struct {
+	err error
+}
+
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	flat := e.Flatten(o.err)
+	enc.AddString("error", flat.Error)
+	if flat.Code != "" {
+		enc.AddString("code", flat.Code)
+	}
+	if flat.Kind != "" {
+		enc.AddString("kind", string(flat.Kind))
+	}
+	if flat.Message != "" {
+		enc.AddString("message", flat.Message)
+	}
+	if flat.ID != "" {
+		enc.AddString("id", flat.ID)
+	}
+	if flat.Retryable {
+		enc.AddBool("retryable", true)
+	}
+	if flat.Upstream != nil {
+		upstream := *flat.Upstream
+		if err := enc.AddObject("upstream", zapcore.ObjectMarshalerFunc(func(inner zapcore.ObjectEncoder) error {
+			inner.AddString("system", upstream.System)
+			if upstream.Endpoint != "" {
+				inner.AddString("endpoint", upstream.Endpoint)
+			}
+			if upstream.Status != 0 {
+				inner.AddInt("status", upstream.Status)
+			}
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	if !flat.Timestamp.IsZero() {
+		enc.AddTime("timestamp", flat.Timestamp)
+	}
+	if len(flat.Ops) > 0 {
+		if err := enc.AddArray("ops", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+			for _, op := range flat.Ops {
+				arr.AppendString(op)
+			}
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	if len(flat.Infos) > 0 {
+		if err := enc.AddArray("infos", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+			for _, info := range flat.Infos {
+				arr.AppendString(info)
+			}
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	if len(flat.Fields) > 0 {
+		if err := enc.AddObject("fields", zapcore.ObjectMarshalerFunc(func(inner zapcore.ObjectEncoder) error {
+			for k, v := range flat.Fields {
+				if err := inner.AddReflected(k, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	if flat.Stacktrace != "" {
+		enc.AddString("stacktrace", flat.Stacktrace)
+	}
+	return nil
+}
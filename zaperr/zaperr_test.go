@@ -0,0 +1,49 @@
+package zaperr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kisunji/e"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestError(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+
+	err := e.Wrap(e.NewError(e.CodeNotFound, "cannot find bar"), "bar id: 2hs8qh9").
+		SetMessage("Bar does not exist").
+		SetKind(e.KindClient).
+		SetField("bar_id", "2hs8qh9").
+		SetUpstream("bardb", "GET /bars", 404)
+	logger.Error("request failed", Error(err))
+
+	got, ok := logs.All()[0].ContextMap()["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected structured error but got %v", logs.All()[0].ContextMap())
+	}
+	want := map[string]interface{}{
+		"error":      "TestError: (bar id: 2hs8qh9): TestError: [not_found] cannot find bar",
+		"code":       e.CodeNotFound,
+		"kind":       string(e.KindClient),
+		"message":    "Bar does not exist",
+		"ops":        []interface{}{"TestError", "TestError"},
+		"infos":      []interface{}{"bar id: 2hs8qh9"},
+		"upstream":   map[string]interface{}{"system": "bardb", "endpoint": "GET /bars", "status": 404},
+		"fields":     map[string]interface{}{"bar_id": "2hs8qh9"},
+		"stacktrace": e.ErrorStacktrace(err),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestErrorNil(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	zap.New(core).Info("no error", Error(nil))
+	if _, ok := logs.All()[0].ContextMap()["error"]; ok {
+		t.Errorf("expected nil error to be skipped")
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/kisunji/e/zerologerr
+
+go 1.25.0
+
+require (
+	github.com/kisunji/e v0.0.0-00010101000000-000000000000
+	github.com/rs/zerolog v1.35.1
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.15 // indirect
+	github.com/mattn/go-isatty v0.0.24 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+)
+
+replace github.com/kisunji/e => ../
@@ -0,0 +1,77 @@
+// Package zerologerr logs errors from package e as structured zerolog
+// objects, using the same keys as package zaperr.
+package zerologerr
+
+import (
+	"github.com/kisunji/e"
+	"github.com/rs/zerolog"
+)
+
+// Error adds err to ev as an object with key "error" containing the error
+// string, code, kind, message, id, retryability, upstream, timestamp, ops,
+// infos, fields and stacktrace.
+// ev is returned unchanged if err is nil.
+//
+// Usage:
+//
+//	zerologerr.Error(log.Error(), err).Msg("cannot process bar")
+func Error(ev *zerolog.Event, err error) *zerolog.Event {
+	if err == nil {
+		return ev
+	}
+	return ev.Object("error", Object(err))
+}
+
+// Object adapts err to zerolog.LogObjectMarshaler.
+func Object(err error) zerolog.LogObjectMarshaler {
+	return object{err: err}
+}
+
+type object struct {
+	err error
+}
+
+func (o object) MarshalZerologObject(ev *zerolog.Event) {
+	flat := e.Flatten(o.err)
+	ev.Str("error", flat.Error)
+	if flat.Code != "" {
+		ev.Str("code", flat.Code)
+	}
+	if flat.Kind != "" {
+		ev.Str("kind", string(flat.Kind))
+	}
+	if flat.Message != "" {
+		ev.Str("message", flat.Message)
+	}
+	if flat.ID != "" {
+		ev.Str("id", flat.ID)
+	}
+	if flat.Retryable {
+		ev.Bool("retryable", true)
+	}
+	if flat.Upstream != nil {
+		upstream := zerolog.Dict().Str("system", flat.Upstream.System)
+		if flat.Upstream.Endpoint != "" {
+			upstream.Str("endpoint", flat.Upstream.Endpoint)
+		}
+		if flat.Upstream.Status != 0 {
+			upstream.Int("status", flat.Upstream.Status)
+		}
+		ev.Dict("upstream", upstream)
+	}
+	if !flat.Timestamp.IsZero() {
+		ev.Time("timestamp", flat.Timestamp)
+	}
+	if len(flat.Ops) > 0 {
+		ev.Strs("ops", flat.Ops)
+	}
+	if len(flat.Infos) > 0 {
+		ev.Strs("infos", flat.Infos)
+	}
+	if len(flat.Fields) > 0 {
+		ev.Interface("fields", flat.Fields)
+	}
+	if flat.Stacktrace != "" {
+		ev.Str("stacktrace", flat.Stacktrace)
+	}
+}
@@ -0,0 +1,69 @@
+package zerologerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kisunji/e"
+	"github.com/rs/zerolog"
+)
+
+func TestError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	err := e.Wrap(e.NewError(e.CodeNotFound, "cannot find bar"), "bar id: 2hs8qh9").
+		SetMessage("Bar does not exist").
+		SetKind(e.KindClient).
+		SetField("bar_id", "2hs8qh9").
+		SetUpstream("bardb", "GET /bars", 404)
+	Error(logger.Error(), err).Msg("request failed")
+
+	var line struct {
+		Error map[string]interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]interface{}{
+		"error":      "TestError: (bar id: 2hs8qh9): TestError: [not_found] cannot find bar",
+		"code":       e.CodeNotFound,
+		"kind":       string(e.KindClient),
+		"message":    "Bar does not exist",
+		"ops":        []interface{}{"TestError", "TestError"},
+		"infos":      []interface{}{"bar id: 2hs8qh9"},
+		"upstream":   map[string]interface{}{"system": "bardb", "endpoint": "GET /bars", "status": 404.0},
+		"fields":     map[string]interface{}{"bar_id": "2hs8qh9"},
+		"stacktrace": e.ErrorStacktrace(err),
+	}
+	if !reflect.DeepEqual(line.Error, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", line.Error, want)
+	}
+}
+
+func TestErrorTimestamp(t *testing.T) {
+	e.SetTimestamps(true)
+	e.SetTestClock(func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) })
+	t.Cleanup(func() {
+		e.SetTimestamps(false)
+		e.SetTestClock(nil)
+	})
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	Error(logger.Error(), e.NewError(e.CodeNotFound, "cannot find bar")).Msg("request failed")
+	var line struct {
+		Error struct {
+			Timestamp string `json:"timestamp"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "2020-01-01T00:00:00Z"; line.Error.Timestamp != want {
+		t.Errorf("\ngot:  %q\nwant: %q", line.Error.Timestamp, want)
+	}
+}